@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+)
+
+const apiKeyUsage = "usage: apikey create <name> | apikey revoke <id> | apikey list | apikey sign <subject> [ttl]"
+
+func runAPIKey(settings config.Settings, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(apiKeyUsage)
+	}
+
+	if args[0] == "sign" {
+		return signAdminToken(settings, args[1:], out)
+	}
+
+	repositories, closeRepositories, err := openRepositories(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeRepositories()
+
+	apiKeyStorage := repositories.APIKeys
+
+	switch args[0] {
+	case "create":
+		if len(args) != 2 {
+			return errors.New(apiKeyUsage)
+		}
+
+		apiKey, key, err := apiKeyStorage.Create(args[1])
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "created API key %d (%s)\n%s\n", apiKey.APIKeyID, apiKey.Name, key)
+
+		return nil
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New(apiKeyUsage)
+		}
+
+		apiKeyID, err := strconv.ParseInt(args[1], 10, 64)
+
+		if err != nil {
+			return fmt.Errorf("invalid API key ID %s", args[1])
+		}
+
+		if err := apiKeyStorage.Revoke(apiKeyID); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "revoked API key %d\n", apiKeyID)
+
+		return nil
+	case "list":
+		apiKeys, err := apiKeyStorage.List()
+
+		if err != nil {
+			return err
+		}
+
+		for _, apiKey := range apiKeys {
+			status := "active"
+			if apiKey.RevokedAt.Valid {
+				status = fmt.Sprintf("revoked %s", apiKey.RevokedAt.Time.Format(time.RFC3339))
+			}
+
+			fmt.Fprintf(out, "%d\t%s\t%s\t%s\n", apiKey.APIKeyID, apiKey.Name, apiKey.CreatedAt.Format(time.RFC3339), status)
+		}
+
+		return nil
+	default:
+		return errors.New(apiKeyUsage)
+	}
+}
+
+func signAdminToken(settings config.Settings, args []string, out io.Writer) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New(apiKeyUsage)
+	}
+
+	if settings.Admin.TokenSecret == "" {
+		return fmt.Errorf("admin token secret is not configured")
+	}
+
+	ttl := time.Hour
+
+	if len(args) == 2 {
+		parsedTTL, err := time.ParseDuration(args[1])
+
+		if err != nil {
+			return fmt.Errorf("invalid ttl %s", args[1])
+		}
+
+		ttl = parsedTTL
+	}
+
+	token, err := domain.SignAdminToken([]byte(settings.Admin.TokenSecret), args[0], time.Now().Add(ttl))
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, token)
+
+	return nil
+}
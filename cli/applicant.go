@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+)
+
+const applicantUsage = "usage: applicant show <nuid> | applicant delete <nuid>"
+
+func runApplicant(settings config.Settings, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(applicantUsage)
+	}
+
+	nuid, err := parseNUIDArg(args[1:], applicantUsage)
+
+	if err != nil {
+		return err
+	}
+
+	repositories, closeRepositories, err := openRepositories(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeRepositories()
+
+	cohort := settings.Application.ActiveCohort()
+
+	switch args[0] {
+	case "show":
+		applicant, err := repositories.Admin.Applicant(cohort, nuid)
+
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("no applicant with NUID %s", nuid)
+		} else if err != nil {
+			return err
+		}
+
+		submissions, err := repositories.Admin.Submissions(cohort, nuid)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "nuid\t%s\n", applicant.NUID.String)
+		fmt.Fprintf(out, "name\t%s\n", applicant.ApplicantName.String)
+		fmt.Fprintf(out, "registered\t%s\n", applicant.RegistrationTime.Time.Format(time.RFC3339))
+		fmt.Fprintf(out, "submissions\t%d\n", len(submissions))
+
+		for _, submission := range submissions {
+			fmt.Fprintf(out, "%d\t%s\tcorrect=%t\tscore=%d\tlate=%t\n", submission.SubmissionID, submission.SubmissionTime.Time.Format(time.RFC3339), submission.Correct.Bool, submission.Score.Int64, submission.Late.Bool)
+		}
+
+		return nil
+	case "delete":
+		err := repositories.Admin.DeleteApplicant(cohort, nuid)
+
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("no applicant with NUID %s", nuid)
+		} else if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "deleted applicant %s\n", nuid)
+
+		return nil
+	default:
+		return errors.New(applicantUsage)
+	}
+}
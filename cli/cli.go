@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+)
+
+const usage = `usage: generate_coding_challenge_server_go <command> [arguments]
+
+commands:
+  serve                                run the HTTP server (the default)
+  migrate up | down [steps|all] | status
+                                       apply, roll back, or list database migrations
+  seed [-count n]                      register sample applicants and submissions
+  export [-format f] [-output file]    export applicants as csv or ndjson
+  grade <nuid> [answers.json]          grade answers against an applicant's solution
+  applicant show <nuid> | delete <nuid>
+                                       inspect or delete an applicant
+  token rotate <nuid>                  issue a new token for an applicant
+  token revoke <nuid>                  revoke an applicant's token
+  token history <nuid>                 show when and by whom a token changed
+  apikey create <name> | revoke <id> | list | sign <subject> [ttl]
+                                       manage admin API keys
+
+applicant, token, grade, seed and export act on the cohort set by application.cohort.`
+
+func Run(args []string) error {
+	settings, err := config.GetConfiguration()
+
+	if err != nil {
+		return err
+	}
+
+	return Execute(settings, args, os.Stdout)
+}
+
+func Execute(settings config.Settings, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return serve(settings)
+	}
+
+	switch args[0] {
+	case "serve":
+		return serve(settings)
+	case "migrate":
+		return runMigrate(settings, args[1:], out)
+	case "seed":
+		return runSeed(settings, args[1:], out)
+	case "export":
+		return runExport(settings, args[1:], out)
+	case "grade":
+		return runGrade(settings, args[1:], out)
+	case "applicant":
+		return runApplicant(settings, args[1:], out)
+	case "token":
+		return runToken(settings, args[1:], out)
+	case "apikey":
+		return runAPIKey(settings, args[1:], out)
+	case "help", "-h", "--help":
+		fmt.Fprintln(out, usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command: %s\n%s", args[0], usage)
+	}
+}
+
+func openRepositories(settings config.Settings) (storage.Repositories, func() error, error) {
+	challenges := domain.DefaultChallengeRegistry()
+
+	generator, err := storage.NewChallengeGenerator(settings, challenges)
+
+	if err != nil {
+		return storage.Repositories{}, nil, err
+	}
+
+	repositories, closeRepositories, err := storage.OpenRepositories(settings, challenges, generator)
+
+	if err != nil {
+		return storage.Repositories{}, nil, fmt.Errorf("failed to connect to the database: %w", err)
+	}
+
+	return repositories, closeRepositories, nil
+}
+
+func syncDefaultCohort(settings config.Settings, repositories storage.Repositories) error {
+	generator, err := storage.NewChallengeGenerator(settings, domain.DefaultChallengeRegistry())
+
+	if err != nil {
+		return err
+	}
+
+	return storage.SyncDefaultCohort(repositories.Cohorts, generator, time.Now())
+}
+
+func parseNUIDArg(args []string, commandUsage string) (domain.NUID, error) {
+	if len(args) != 1 {
+		return "", errors.New(commandUsage)
+	}
+
+	nuid, err := domain.ParseNUID(args[0])
+
+	if err != nil {
+		return "", fmt.Errorf("invalid NUID %s", args[0])
+	}
+
+	return *nuid, nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/export"
+)
+
+func runExport(settings config.Settings, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	rawFormat := flags.String("format", string(export.FormatCSV), "output format (csv or ndjson)")
+	output := flags.String("output", "", "file to write to (defaults to stdout)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(*rawFormat)
+
+	if err != nil {
+		return err
+	}
+
+	repositories, closeRepositories, err := openRepositories(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeRepositories()
+
+	if *output != "" {
+		file, err := os.Create(*output)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		out = file
+	}
+
+	w := bufio.NewWriter(out)
+
+	if err := export.Applicants(repositories.Admin, settings.Application.ActiveCohort(), format, w); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+)
+
+const gradeUsage = "usage: grade <nuid> [answers.json]"
+
+func runGrade(settings config.Settings, args []string, out io.Writer) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New(gradeUsage)
+	}
+
+	nuid, err := parseNUIDArg(args[:1], gradeUsage)
+
+	if err != nil {
+		return err
+	}
+
+	input := os.Stdin
+
+	if len(args) == 2 && args[1] != "-" {
+		input, err = os.Open(args[1])
+
+		if err != nil {
+			return err
+		}
+
+		defer input.Close()
+	}
+
+	var answers []string
+
+	if err := json.NewDecoder(input).Decode(&answers); err != nil {
+		return fmt.Errorf("answers must be a JSON array of strings: %w", err)
+	}
+
+	repositories, closeRepositories, err := openRepositories(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeRepositories()
+
+	submission, err := repositories.Admin.Solution(settings.Application.ActiveCohort(), nuid)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("no applicant with NUID %s", nuid)
+	} else if err != nil {
+		return err
+	}
+
+	generator, err := domain.DefaultChallengeRegistry().Get(submission.ChallengeType.String, int(submission.ChallengeVersion.Int64))
+
+	if err != nil {
+		return err
+	}
+
+	grade := generator.Grade(submission.Solution, answers)
+
+	for i, result := range grade.Results {
+		if result == domain.CaseCorrect {
+			continue
+		}
+
+		var expected, given string
+
+		if i < len(submission.Solution) {
+			expected = submission.Solution[i]
+		}
+
+		if i < len(answers) {
+			given = answers[i]
+		}
+
+		fmt.Fprintf(out, "%d\t%s\texpected %q\tgiven %q\n", i, result, expected, given)
+	}
+
+	fmt.Fprintf(out, "score %d/%d (%.2f%%) correct=%t\n", grade.Score, len(grade.Results), grade.Percentage, grade.Correct())
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/db"
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateUsage = "usage: migrate up | migrate down [steps|all] | migrate status"
+
+func runMigrate(settings config.Settings, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	m, err := db.NewMigrate(settings.Database)
+
+	if err != nil {
+		return err
+	}
+
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return errors.New(migrateUsage)
+		}
+
+		if err := m.Up(); errors.Is(err, migrate.ErrNoChange) {
+			fmt.Fprintln(out, "database is already up to date")
+			return prepareDatabase(settings, out)
+		} else if err != nil {
+			return err
+		}
+
+		if err := prepareDatabase(settings, out); err != nil {
+			return err
+		}
+	case "down":
+		if len(args) > 2 {
+			return errors.New(migrateUsage)
+		}
+
+		if len(args) == 2 && args[1] == "all" {
+			err = m.Down()
+		} else {
+			steps := 1
+
+			if len(args) == 2 {
+				steps, err = strconv.Atoi(args[1])
+
+				if err != nil || steps < 1 {
+					return fmt.Errorf("invalid number of steps %s", args[1])
+				}
+			}
+
+			err = m.Steps(-steps)
+		}
+
+		if errors.Is(err, migrate.ErrNoChange) {
+			fmt.Fprintln(out, "no migrations to roll back")
+			return nil
+		} else if err != nil {
+			return err
+		}
+	case "status":
+		if len(args) != 1 {
+			return errors.New(migrateUsage)
+		}
+
+		return printMigrationStatus(settings, m, out)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return printMigrationStatus(settings, m, out)
+}
+
+func prepareDatabase(settings config.Settings, out io.Writer) error {
+	repositories, closeRepositories, err := openRepositories(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeRepositories()
+
+	hashed, err := repositories.Admin.HashLegacyTokens()
+
+	if err != nil {
+		return err
+	}
+
+	if hashed > 0 {
+		fmt.Fprintf(out, "hashed %d legacy tokens\n", hashed)
+	}
+
+	return syncDefaultCohort(settings, repositories)
+}
+
+func printMigrationStatus(settings config.Settings, m *migrate.Migrate, out io.Writer) error {
+	current, dirty, err := m.Version()
+
+	if errors.Is(err, migrate.ErrNilVersion) {
+		current = 0
+	} else if err != nil {
+		return err
+	}
+
+	versions, err := db.MigrationVersions(settings.Database)
+
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		status := "pending"
+
+		if version <= current {
+			status = "applied"
+		}
+
+		if version == current && dirty {
+			status = "dirty"
+		}
+
+		fmt.Fprintf(out, "%d\t%s\n", version, status)
+	}
+
+	return nil
+}
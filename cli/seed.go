@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+)
+
+var seedNames = []string{"Ada Lovelace", "Alan Turing", "Grace Hopper", "Edsger Dijkstra", "Barbara Liskov", "Donald Knuth", "Margaret Hamilton", "Ken Thompson"}
+
+func runSeed(settings config.Settings, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := flags.Int("count", 10, "number of applicants to register")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *count < 1 || flags.NArg() != 0 {
+		return fmt.Errorf("usage: seed [-count n]")
+	}
+
+	repositories, closeRepositories, err := openRepositories(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeRepositories()
+
+	if err := syncDefaultCohort(settings, repositories); err != nil {
+		return err
+	}
+
+	for i := 0; i < *count; i++ {
+		nuid := domain.NUID(fmt.Sprintf("%09d", domain.GenerateRandomInt(1_000_000_000)))
+		name := domain.ApplicantName(seedNames[i%len(seedNames)])
+
+		email := domain.Email(fmt.Sprintf("applicant%s@example.com", nuid))
+
+		result, err := repositories.Applicants.Register(domain.Applicant{Cohort: settings.Application.ActiveCohort(), NUID: nuid, Name: name, Email: email})
+
+		if errors.Is(err, storage.ErrAlreadyRegistered) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		status := "registered"
+
+		switch i % 3 {
+		case 0, 1:
+			submission, err := repositories.Applicants.Submit(result.Token, nil)
+
+			if err != nil {
+				return err
+			}
+
+			answers := append([]string{}, submission.Solution...)
+
+			if i%3 == 1 && len(answers) > 0 {
+				answers = answers[1:]
+			}
+
+			outcome, err := repositories.Applicants.WriteSubmit(nuid, submission, answers, storage.SubmissionPolicy{})
+
+			if err != nil {
+				return err
+			}
+
+			status = fmt.Sprintf("submitted %d/%d", outcome.Grade.Score, len(outcome.Grade.Results))
+		}
+
+		fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", nuid, name, result.Token, status)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/mailer"
+	"github.com/garrettladley/generate_coding_challenge_server_go/ratelimit"
+	"github.com/garrettladley/generate_coding_challenge_server_go/server"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"go.uber.org/fx"
+)
+
+func serve(settings config.Settings) error {
+	app := fx.New(
+		fx.Supply(settings),
+		fx.Provide(
+			domain.DefaultChallengeRegistry,
+			storage.NewChallengeGenerator,
+			storage.NewRepositories,
+			mailer.NewMailer,
+			handlers.NewAdminHandler,
+			handlers.NewApplicantHandler,
+			handlers.NewAuthHandler,
+			handlers.NewRateLimitHandler,
+			ratelimit.NewStore,
+		),
+		fx.Invoke(server.NewFxFiberApp),
+	)
+
+	if err := app.Err(); err != nil {
+		return err
+	}
+
+	app.Run()
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+)
+
+const (
+	tokenUsage = "usage: token rotate|revoke|history <nuid>"
+	cliActor   = "cli"
+)
+
+func runToken(settings config.Settings, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(tokenUsage)
+	}
+
+	nuid, err := parseNUIDArg(args[1:], tokenUsage)
+
+	if err != nil {
+		return err
+	}
+
+	repositories, closeRepositories, err := openRepositories(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer closeRepositories()
+
+	cohort := settings.Application.ActiveCohort()
+
+	switch args[0] {
+	case "rotate":
+		token, err := repositories.Admin.RotateToken(cohort, nuid, cliActor)
+
+		if err != nil {
+			return tokenError(nuid, err)
+		}
+
+		fmt.Fprintln(out, token)
+	case "revoke":
+		if err := repositories.Admin.RevokeToken(cohort, nuid, cliActor); err != nil {
+			return tokenError(nuid, err)
+		}
+
+		fmt.Fprintf(out, "revoked token for %s\n", nuid)
+	case "history":
+		history, err := repositories.Admin.TokenHistory(cohort, nuid)
+
+		if err != nil {
+			return tokenError(nuid, err)
+		}
+
+		for _, entry := range history {
+			fmt.Fprintf(out, "%s\t%s\t%s\n", entry.ChangedAt.Format(time.RFC3339), entry.Action, entry.Actor)
+		}
+	default:
+		return errors.New(tokenUsage)
+	}
+
+	return nil
+}
+
+func tokenError(nuid domain.NUID, err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("no applicant with NUID %s", nuid)
+	}
+
+	return err
+}
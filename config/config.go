@@ -5,42 +5,199 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
 	"github.com/spf13/viper"
 )
 
 type Settings struct {
 	Database    DatabaseSettings    `yaml:"database"`
 	Application ApplicationSettings `yaml:"application"`
+	Challenge   ChallengeSettings   `yaml:"challenge"`
+	Admin       AdminSettings       `yaml:"admin"`
+	Mail        MailSettings        `yaml:"mail"`
+}
+
+func (s *Settings) Validate() error {
+	if err := s.Application.Validate(); err != nil {
+		return err
+	}
+
+	return s.Challenge.Validate()
 }
 
 type ProductionSettings struct {
 	Database    ProductionDatabaseSettings    `yaml:"database"`
 	Application ProductionApplicationSettings `yaml:"application"`
+	Challenge   ChallengeSettings             `yaml:"challenge"`
+	Mail        ProductionMailSettings        `yaml:"mail"`
 }
 
 type ApplicationSettings struct {
-	Port    uint16 `yaml:"port"`
-	Host    string `yaml:"host"`
-	BaseUrl string `yaml:"baseurl"`
+	Port               uint16            `yaml:"port"`
+	Host               string            `yaml:"host"`
+	BaseUrl            string            `yaml:"baseurl"`
+	LegacyRoutesSunset string            `yaml:"legacyroutessunset"`
+	RateLimit          RateLimitSettings `yaml:"ratelimit"`
+	TokenHashKey       string            `yaml:"tokenhashkey"`
+	Cohort             string            `yaml:"cohort"`
 }
 
 type ProductionApplicationSettings struct {
-	Port uint16 `yaml:"port"`
-	Host string `yaml:"host"`
+	Port               uint16            `yaml:"port"`
+	Host               string            `yaml:"host"`
+	LegacyRoutesSunset string            `yaml:"legacyroutessunset"`
+	RateLimit          RateLimitSettings `yaml:"ratelimit"`
+	Cohort             string            `yaml:"cohort"`
+}
+
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+)
+
+type RateLimitSettings struct {
+	Store    string                            `yaml:"store"`
+	IPHeader string                            `yaml:"ipheader"`
+	Routes   map[string]RouteRateLimitSettings `yaml:"routes"`
 }
 
+type RouteRateLimitSettings struct {
+	PerIP  RateLimit `yaml:"perip"`
+	PerKey RateLimit `yaml:"perkey"`
+}
+
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+func (s *ApplicationSettings) ActiveCohort() domain.CohortName {
+	if s.Cohort == "" {
+		return domain.DefaultCohortName
+	}
+
+	return domain.CohortName(s.Cohort)
+}
+
+func (s *ApplicationSettings) Validate() error {
+	if s.LegacyRoutesSunset != "" {
+		if _, err := time.Parse(time.RFC3339, s.LegacyRoutesSunset); err != nil {
+			return fmt.Errorf("invalid application.legacyroutessunset %s: %w", s.LegacyRoutesSunset, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ApplicationSettings) LegacySunset() *time.Time {
+	if s.LegacyRoutesSunset == "" {
+		return nil
+	}
+
+	sunset, err := time.Parse(time.RFC3339, s.LegacyRoutesSunset)
+
+	if err != nil {
+		return nil
+	}
+
+	return &sunset
+}
+
+type ChallengeSettings struct {
+	Type               string        `yaml:"type"`
+	Version            int           `yaml:"version"`
+	RevealScore        bool          `yaml:"revealscore"`
+	MaxAttempts        int           `yaml:"maxattempts"`
+	MinAttemptInterval time.Duration `yaml:"minattemptinterval"`
+	CloseTime          string        `yaml:"closetime"`
+	TimeBudget         time.Duration `yaml:"timebudget"`
+	LateSubmissions    string        `yaml:"latesubmissions"`
+}
+
+const (
+	LateSubmissionsReject = "reject"
+	LateSubmissionsFlag   = "flag"
+)
+
+func (s *ChallengeSettings) Validate() error {
+	if s.CloseTime != "" {
+		if _, err := time.Parse(time.RFC3339, s.CloseTime); err != nil {
+			return fmt.Errorf("invalid challenge.closetime %s: %w", s.CloseTime, err)
+		}
+	}
+
+	switch s.LateSubmissions {
+	case "", LateSubmissionsReject, LateSubmissionsFlag:
+	default:
+		return fmt.Errorf("invalid challenge.latesubmissions %s", s.LateSubmissions)
+	}
+
+	return nil
+}
+
+func (s *ChallengeSettings) CloseAt() *time.Time {
+	if s.CloseTime == "" {
+		return nil
+	}
+
+	closeTime, err := time.Parse(time.RFC3339, s.CloseTime)
+
+	if err != nil {
+		return nil
+	}
+
+	return &closeTime
+}
+
+type AdminSettings struct {
+	TokenSecret string `yaml:"tokensecret"`
+}
+
+const (
+	MailDriverOutbox = "outbox"
+	MailDriverSMTP   = "smtp"
+)
+
+type MailSettings struct {
+	Driver     string `yaml:"driver"`
+	Host       string `yaml:"host"`
+	Port       uint16 `yaml:"port"`
+	Username   string `yaml:"username"`
+	Password   string `yaml:"password"`
+	From       string `yaml:"from"`
+	OutboxPath string `yaml:"outboxpath"`
+}
+
+type ProductionMailSettings struct {
+	Driver string `yaml:"driver"`
+	Port   uint16 `yaml:"port"`
+}
+
+const (
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+	DriverSQLite   = "sqlite"
+)
+
 type DatabaseSettings struct {
+	Driver       string `yaml:"driver"`
 	Username     string `yaml:"username"`
 	Password     string `yaml:"password"`
 	Port         uint16 `yaml:"port"`
 	Host         string `yaml:"host"`
 	DatabaseName string `yaml:"databasename"`
 	RequireSSL   bool   `yaml:"requiressl"`
+	Path         string `yaml:"path"`
+	AutoMigrate  bool   `yaml:"automigrate"`
 }
 
 type ProductionDatabaseSettings struct {
-	RequireSSL bool `yaml:"requiressl"`
+	Driver      string `yaml:"driver"`
+	RequireSSL  bool   `yaml:"requiressl"`
+	AutoMigrate bool   `yaml:"automigrate"`
 }
 
 func (s *DatabaseSettings) WithoutDb() string {
@@ -59,6 +216,10 @@ func (s *DatabaseSettings) WithDb() string {
 	return fmt.Sprintf("%s dbname=%s", s.WithoutDb(), s.DatabaseName)
 }
 
+func (s *DatabaseSettings) SQLiteDSN() string {
+	return fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", s.Path)
+}
+
 type Environment string
 
 const (
@@ -99,6 +260,10 @@ func GetConfiguration() (Settings, error) {
 			return settings, fmt.Errorf("failed to unmarshal configuration: %w", err)
 		}
 
+		if err := settings.Validate(); err != nil {
+			return settings, err
+		}
+
 		return settings, nil
 	} else {
 		var prodSettings ProductionSettings
@@ -116,6 +281,8 @@ func GetConfiguration() (Settings, error) {
 		appPrefix := "APP_"
 		dbPrefix := fmt.Sprintf("%sDATABASE__", appPrefix)
 		applicationPrefix := fmt.Sprintf("%sAPPLICATION__", appPrefix)
+		adminPrefix := fmt.Sprintf("%sADMIN__", appPrefix)
+		mailPrefix := fmt.Sprintf("%sMAIL__", appPrefix)
 
 		portStr := os.Getenv(fmt.Sprintf("%sPORT", appPrefix))
 		portInt, err := (strconv.Atoi(portStr))
@@ -124,20 +291,45 @@ func GetConfiguration() (Settings, error) {
 			return Settings{}, fmt.Errorf("failed to parse port: %w", err)
 		}
 
-		return Settings{
+		settings := Settings{
 			Database: DatabaseSettings{
+				Driver:       prodSettings.Database.Driver,
 				Username:     os.Getenv(fmt.Sprintf("%sUSERNAME", dbPrefix)),
 				Password:     os.Getenv(fmt.Sprintf("%sPASSWORD", dbPrefix)),
 				Host:         os.Getenv(fmt.Sprintf("%sHOST", dbPrefix)),
 				Port:         uint16(portInt),
 				DatabaseName: os.Getenv(fmt.Sprintf("%sDATABASE_NAME", dbPrefix)),
 				RequireSSL:   prodSettings.Database.RequireSSL,
+				Path:         os.Getenv(fmt.Sprintf("%sPATH", dbPrefix)),
+				AutoMigrate:  prodSettings.Database.AutoMigrate,
 			},
 			Application: ApplicationSettings{
-				Port:    prodSettings.Application.Port,
-				Host:    prodSettings.Application.Host,
-				BaseUrl: os.Getenv(fmt.Sprintf("%sBASE_URL", applicationPrefix)),
+				Port:               prodSettings.Application.Port,
+				Host:               prodSettings.Application.Host,
+				BaseUrl:            os.Getenv(fmt.Sprintf("%sBASE_URL", applicationPrefix)),
+				LegacyRoutesSunset: prodSettings.Application.LegacyRoutesSunset,
+				RateLimit:          prodSettings.Application.RateLimit,
+				TokenHashKey:       os.Getenv(fmt.Sprintf("%sTOKEN_HASH_KEY", applicationPrefix)),
+				Cohort:             prodSettings.Application.Cohort,
+			},
+			Challenge: prodSettings.Challenge,
+			Admin: AdminSettings{
+				TokenSecret: os.Getenv(fmt.Sprintf("%sTOKEN_SECRET", adminPrefix)),
 			},
-		}, nil
+			Mail: MailSettings{
+				Driver:   prodSettings.Mail.Driver,
+				Host:     os.Getenv(fmt.Sprintf("%sHOST", mailPrefix)),
+				Port:     prodSettings.Mail.Port,
+				Username: os.Getenv(fmt.Sprintf("%sUSERNAME", mailPrefix)),
+				Password: os.Getenv(fmt.Sprintf("%sPASSWORD", mailPrefix)),
+				From:     os.Getenv(fmt.Sprintf("%sFROM", mailPrefix)),
+			},
+		}
+
+		if err := settings.Validate(); err != nil {
+			return Settings{}, err
+		}
+
+		return settings, nil
 	}
 }
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+func migrationSource(driver string) (source.Driver, error) {
+	switch driver {
+	case "", config.DriverPostgres:
+		return iofs.New(migrations.Postgres, ".")
+	case config.DriverSQLite:
+		return iofs.New(migrations.SQLite, "sqlite")
+	default:
+		return nil, fmt.Errorf("database driver %s does not support migrations", driver)
+	}
+}
+
+func openMigrationDriver(settings config.DatabaseSettings) (database.Driver, error) {
+	switch settings.Driver {
+	case "", config.DriverPostgres:
+		conn, err := sql.Open("postgres", settings.WithDb())
+
+		if err != nil {
+			return nil, err
+		}
+
+		driver, err := postgres.WithInstance(conn, &postgres.Config{})
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return driver, nil
+	case config.DriverSQLite:
+		conn, err := sql.Open("sqlite3", settings.SQLiteDSN())
+
+		if err != nil {
+			return nil, err
+		}
+
+		driver, err := sqlite3.WithInstance(conn, &sqlite3.Config{})
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return driver, nil
+	default:
+		return nil, fmt.Errorf("database driver %s does not support migrations", settings.Driver)
+	}
+}
+
+func MigrationVersions(settings config.DatabaseSettings) ([]uint, error) {
+	src, err := migrationSource(settings.Driver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	version, err := src.First()
+
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []uint{version}
+
+	for {
+		version, err = src.Next(version)
+
+		if errors.Is(err, fs.ErrNotExist) {
+			return versions, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+}
+
+func NewMigrate(settings config.DatabaseSettings) (*migrate.Migrate, error) {
+	src, err := migrationSource(settings.Driver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := openMigrationDriver(settings)
+
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, settings.Driver, driver)
+
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func Migrate(settings config.DatabaseSettings) error {
+	m, err := NewMigrate(settings)
+
+	if err != nil {
+		return err
+	}
+
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return nil
+}
@@ -3,17 +3,20 @@ package db
 import (
 	"github.com/garrettladley/generate_coding_challenge_server_go/config"
 	"github.com/jmoiron/sqlx"
-	"go.uber.org/fx"
 )
 
-func CreatePostgresConnection(lc fx.Lifecycle, settings config.Settings) *sqlx.DB {
-	db := sqlx.MustConnect("postgres", settings.Database.WithDb())
+func OpenPostgresConnection(settings config.Settings) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("postgres", settings.Database.WithDb())
 
-	err := db.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return db
+	return db, nil
 }
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func OpenSQLiteConnection(settings config.Settings) (*sqlx.DB, error) {
+	if settings.Database.Path == "" {
+		return nil, errors.New("the sqlite driver requires database.path to be set")
+	}
+
+	db, err := sqlx.Connect("sqlite3", settings.Database.SQLiteDSN())
+
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AdminTokenClaims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+func SignAdminToken(secret []byte, subject string, expiresAt time.Time) (string, error) {
+	payload, err := json.Marshal(AdminTokenClaims{
+		Subject:   subject,
+		ExpiresAt: expiresAt.Unix(),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	return fmt.Sprintf("%s.%s", encodedPayload, signAdminTokenPayload(secret, encodedPayload)), nil
+}
+
+func VerifyAdminToken(secret []byte, token string, now time.Time) (*AdminTokenClaims, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed admin token")
+	}
+
+	expected := signAdminTokenPayload(secret, parts[0])
+
+	if !hmac.Equal([]byte(expected), []byte(parts[1])) {
+		return nil, fmt.Errorf("invalid admin token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+
+	if err != nil {
+		return nil, fmt.Errorf("malformed admin token")
+	}
+
+	var claims AdminTokenClaims
+
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed admin token")
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("admin token is missing a subject")
+	}
+
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("admin token has expired")
+	}
+
+	return &claims, nil
+}
+
+func signAdminTokenPayload(secret []byte, encodedPayload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encodedPayload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
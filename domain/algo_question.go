@@ -29,17 +29,26 @@ func EditTypes() []EditType {
 	}
 }
 
-func GenerateChallenge(nRandom int, mandatoryCases []string) Challenge {
-	randomCases := generateRandomCases(nRandom)
+func GenerateChallenge(seed int64, nRandom int, mandatoryCases []string) Challenge {
+	rng := mrand.New(mrand.NewSource(seed))
+
+	randomCases := generateRandomCases(rng, nRandom)
 
 	allCases := append(mandatoryCases, randomCases...)
 
-	mrand.Shuffle(len(allCases), func(i, j int) {
+	rng.Shuffle(len(allCases), func(i, j int) {
 		allCases[i], allCases[j] = allCases[j], allCases[i]
 	})
 
+	return Challenge{
+		Challenge: allCases,
+		Solution:  solveColorCases(allCases),
+	}
+}
+
+func solveColorCases(cases []string) []string {
 	var answers []string
-	for _, caseColor := range allCases {
+	for _, caseColor := range cases {
 		answer, err := OneEditAway(caseColor)
 		if err != nil {
 			continue
@@ -48,30 +57,27 @@ func GenerateChallenge(nRandom int, mandatoryCases []string) Challenge {
 		answers = append(answers, answerStr)
 	}
 
-	return Challenge{
-		Challenge: allCases,
-		Solution:  answers,
-	}
+	return answers
 }
 
-func generateRandomCases(nRandom int) []string {
+func generateRandomCases(rng *mrand.Rand, nRandom int) []string {
 	randomCases := make([]string, nRandom)
 	colors := Colors()
 	editTypes := EditTypes()
 	alphabet := "abcdefghijklmnopqrstuvwxyz"
 
 	for i := 0; i < nRandom; i++ {
-		randColorIdx := GenerateRandomInt(int64(len(colors)))
+		randColorIdx := rng.Int63n(int64(len(colors)))
 		color := colors[randColorIdx]
 		colorStr, _ := color.String()
 		lenColor := len(colorStr)
-		randomCount := GenerateRandomInt(int64(lenColor + 1))
+		randomCount := rng.Int63n(int64(lenColor + 1))
 		if randomCount == 0 {
 			randomCases[i] = colorStr
 			continue
 		}
 
-		randEditTypeIdx := GenerateRandomInt(int64(len(editTypes)))
+		randEditTypeIdx := rng.Int63n(int64(len(editTypes)))
 		editType := editTypes[randEditTypeIdx]
 
 		switch editType {
@@ -81,12 +87,12 @@ func generateRandomCases(nRandom int) []string {
 			colorChars := []rune(colorStr)
 			randomChars := make([]rune, randomCount)
 			for j := int64(0); j < randomCount; j++ {
-				randomCharIdx := GenerateRandomInt(int64(len(alphabet)))
+				randomCharIdx := rng.Int63n(int64(len(alphabet)))
 				randomChars[j] = rune(alphabet[randomCharIdx])
 			}
 			randomIndices := make([]int, randomCount)
 			for j := int64(0); j < randomCount; j++ {
-				randomIndices[j] = int(GenerateRandomInt(int64(len(colorChars))))
+				randomIndices[j] = int(rng.Int63n(int64(len(colorChars))))
 			}
 			for j := int64(0); j < randomCount; j++ {
 				colorChars = []rune(InsertCharAtIndex(string(colorChars), randomChars[j], randomIndices[j]))
@@ -96,14 +102,14 @@ func generateRandomCases(nRandom int) []string {
 			colorChars := []rune(colorStr)
 			changedIndices := make([]int, randomCount)
 			for j := int64(0); j < randomCount; j++ {
-				randColorIdx := GenerateRandomInt(int64(lenColor))
+				randColorIdx := rng.Int63n(int64(lenColor))
 				changedIndices[j] = int(randColorIdx)
 			}
 			for j := int64(0); j < randomCount; j++ {
 				originalChar := colorChars[changedIndices[j]]
 				var newChar rune
 				for {
-					randCharIdx := GenerateRandomInt(int64(len(alphabet)))
+					randCharIdx := rng.Int63n(int64(len(alphabet)))
 					newChar = rune(alphabet[randCharIdx])
 					if newChar != originalChar {
 						break
@@ -118,6 +124,10 @@ func generateRandomCases(nRandom int) []string {
 	return randomCases
 }
 
+func GenerateSeed() int64 {
+	return GenerateRandomInt(math.MaxInt64)
+}
+
 func GenerateRandomInt(max int64) int64 {
 	randInt, _ := crand.Int(crand.Reader, big.NewInt(max))
 	return randInt.Int64()
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const apiKeyPrefix = "ccs_"
+
+func GenerateAPIKey() (string, error) {
+	raw := make([]byte, 32)
+
+	if _, err := crand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func IsAPIKey(str string) bool {
+	return strings.HasPrefix(str, apiKeyPrefix)
+}
+
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
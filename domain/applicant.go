@@ -1,6 +1,8 @@
 package domain
 
 type Applicant struct {
-	NUID NUID
-	Name ApplicantName
+	Cohort CohortName
+	NUID   NUID
+	Name   ApplicantName
+	Email  Email
 }
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"fmt"
+)
+
+type ChallengeGenerator interface {
+	Name() string
+	Version() int
+	Generate(seed int64) Challenge
+	Solve(cases []string) []string
+	Grade(solution []string, given []string) Grade
+}
+
+type ChallengeRegistry struct {
+	generators map[string]map[int]ChallengeGenerator
+}
+
+func NewChallengeRegistry(generators ...ChallengeGenerator) *ChallengeRegistry {
+	registry := &ChallengeRegistry{generators: make(map[string]map[int]ChallengeGenerator)}
+
+	for _, generator := range generators {
+		registry.Register(generator)
+	}
+
+	return registry
+}
+
+func DefaultChallengeRegistry() *ChallengeRegistry {
+	return NewChallengeRegistry(NewColorOneEditAway())
+}
+
+func (r *ChallengeRegistry) Register(generator ChallengeGenerator) {
+	versions, exists := r.generators[generator.Name()]
+
+	if !exists {
+		versions = make(map[int]ChallengeGenerator)
+		r.generators[generator.Name()] = versions
+	}
+
+	versions[generator.Version()] = generator
+}
+
+func (r *ChallengeRegistry) Get(name string, version int) (ChallengeGenerator, error) {
+	versions, exists := r.generators[name]
+
+	if !exists {
+		return nil, fmt.Errorf("unknown challenge type: %s", name)
+	}
+
+	generator, exists := versions[version]
+
+	if !exists {
+		return nil, fmt.Errorf("unknown version %d of challenge type: %s", version, name)
+	}
+
+	return generator, nil
+}
+
+func (r *ChallengeRegistry) Latest(name string) (ChallengeGenerator, error) {
+	versions, exists := r.generators[name]
+
+	if !exists || len(versions) == 0 {
+		return nil, fmt.Errorf("unknown challenge type: %s", name)
+	}
+
+	var latest ChallengeGenerator
+	for version, generator := range versions {
+		if latest == nil || version > latest.Version() {
+			latest = generator
+		}
+	}
+
+	return latest, nil
+}
+
+func (r *ChallengeRegistry) Resolve(name string, version int) (ChallengeGenerator, error) {
+	if version == 0 {
+		return r.Latest(name)
+	}
+
+	return r.Get(name, version)
+}
+
+func (r *ChallengeRegistry) Regenerate(name string, version int, seed int64) (Challenge, error) {
+	generator, err := r.Get(name, version)
+
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	return generator.Generate(seed), nil
+}
+
+const ColorOneEditAwayName = "color_one_edit_away"
+
+type ColorOneEditAway struct {
+	NRandom        int
+	MandatoryCases []string
+}
+
+func NewColorOneEditAway() *ColorOneEditAway {
+	mandatoryCases := []string{""}
+	for _, color := range Colors() {
+		colorStr, _ := color.String()
+		mandatoryCases = append(mandatoryCases, colorStr)
+	}
+
+	return &ColorOneEditAway{
+		NRandom:        100,
+		MandatoryCases: mandatoryCases,
+	}
+}
+
+func (c *ColorOneEditAway) Name() string {
+	return ColorOneEditAwayName
+}
+
+func (c *ColorOneEditAway) Version() int {
+	return 1
+}
+
+func (c *ColorOneEditAway) Generate(seed int64) Challenge {
+	mandatoryCases := make([]string, len(c.MandatoryCases))
+	copy(mandatoryCases, c.MandatoryCases)
+
+	return GenerateChallenge(seed, c.NRandom, mandatoryCases)
+}
+
+func (c *ColorOneEditAway) Solve(cases []string) []string {
+	return solveColorCases(cases)
+}
+
+func (c *ColorOneEditAway) Grade(solution []string, given []string) Grade {
+	return GradeCases(solution, given)
+}
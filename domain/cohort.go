@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"fmt"
+)
+
+type CohortName string
+
+const DefaultCohortName CohortName = "default"
+
+func ParseCohortName(str string) (*CohortName, error) {
+	if len(str) == 0 || len(str) > 64 {
+		return nil, fmt.Errorf("invalid cohort name! Given: %s", str)
+	}
+
+	for i, c := range str {
+		isAlphanumeric := (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+
+		if !isAlphanumeric && (i == 0 || (c != '-' && c != '_')) {
+			return nil, fmt.Errorf("invalid cohort name! Given: %s", str)
+		}
+	}
+
+	cohort := CohortName(str)
+	return &cohort, nil
+}
+
+func (c CohortName) String() string {
+	return string(c)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func FormatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var builder strings.Builder
+
+	if d < 0 {
+		builder.WriteByte('-')
+		d = -d
+	}
+
+	builder.WriteString("PT")
+
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	nanos := d - seconds*time.Second
+
+	if hours > 0 {
+		fmt.Fprintf(&builder, "%dH", hours)
+	}
+
+	if minutes > 0 {
+		fmt.Fprintf(&builder, "%dM", minutes)
+	}
+
+	if seconds > 0 || nanos > 0 {
+		builder.WriteString(strconv.FormatInt(int64(seconds), 10))
+
+		if nanos > 0 {
+			builder.WriteByte('.')
+			builder.WriteString(strings.TrimRight(fmt.Sprintf("%09d", nanos), "0"))
+		}
+
+		builder.WriteByte('S')
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+)
+
+type Email string
+
+func ParseEmail(str string) (*Email, error) {
+	address, err := mail.ParseAddress(str)
+
+	if err != nil || address.Address != str || len(str) > 254 {
+		return nil, fmt.Errorf("invalid email! Given: %s", str)
+	}
+
+	email := Email(str)
+	return &email, nil
+}
+
+func (e Email) String() string {
+	return string(e)
+}
@@ -0,0 +1,55 @@
+package domain
+
+type CaseResult string
+
+const (
+	CaseCorrect   CaseResult = "correct"
+	CaseIncorrect CaseResult = "incorrect"
+	CaseMissing   CaseResult = "missing"
+	CaseExtra     CaseResult = "extra"
+)
+
+type Grade struct {
+	Results    []CaseResult
+	Score      int
+	Percentage float64
+}
+
+func (g Grade) Correct() bool {
+	return g.Score == len(g.Results)
+}
+
+func GradeCases(solution []string, given []string) Grade {
+	total := len(solution)
+	if len(given) > total {
+		total = len(given)
+	}
+
+	results := make([]CaseResult, total)
+	score := 0
+
+	for i := 0; i < total; i++ {
+		switch {
+		case i >= len(given):
+			results[i] = CaseMissing
+		case i >= len(solution):
+			results[i] = CaseExtra
+		case solution[i] == given[i]:
+			results[i] = CaseCorrect
+			score++
+		default:
+			results[i] = CaseIncorrect
+		}
+	}
+
+	var percentage float64
+	if total > 0 {
+		percentage = float64(score) / float64(total) * 100
+	}
+
+	return Grade{
+		Results:    results,
+		Score:      score,
+		Percentage: percentage,
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type HistogramBucket struct {
+	UpperBound time.Duration
+	Count      int
+}
+
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	weight := rank - float64(lower)
+
+	return sorted[lower] + time.Duration(weight*float64(sorted[upper]-sorted[lower]))
+}
+
+func Histogram(durations []time.Duration, upperBounds []time.Duration) []HistogramBucket {
+	buckets := make([]HistogramBucket, len(upperBounds)+1)
+
+	for i, upperBound := range upperBounds {
+		buckets[i].UpperBound = upperBound
+	}
+
+	buckets[len(upperBounds)].UpperBound = math.MaxInt64
+
+	for _, duration := range durations {
+		for i := range buckets {
+			if duration < buckets[i].UpperBound {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+
+	return buckets
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+type TokenHasher struct {
+	Key []byte
+}
+
+func NewTokenHasher(key string) TokenHasher {
+	return TokenHasher{Key: []byte(key)}
+}
+
+func (h TokenHasher) Hash(token string) string {
+	mac := hmac.New(sha256.New, h.Key)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
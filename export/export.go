@@ -0,0 +1,209 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+func ParseFormat(str string) (Format, error) {
+	switch Format(str) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("invalid export format: %s", str)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "text/csv"
+	}
+}
+
+type Row struct {
+	NUID                    string     `json:"nuid"`
+	ApplicantName           string     `json:"name"`
+	RegistrationTime        time.Time  `json:"registration_time"`
+	Submitted               bool       `json:"submitted"`
+	Correct                 bool       `json:"correct"`
+	Score                   *int64     `json:"score"`
+	Percentage              *float64   `json:"percentage"`
+	Attempts                int64      `json:"attempts"`
+	LatestSubmissionTime    *time.Time `json:"latest_submission_time"`
+	TimeToCompletionSeconds *float64   `json:"time_to_completion_seconds"`
+}
+
+var header = []string{
+	"nuid",
+	"name",
+	"registration_time",
+	"submitted",
+	"correct",
+	"score",
+	"percentage",
+	"attempts",
+	"latest_submission_time",
+	"time_to_completion_seconds",
+}
+
+func NewRow(row storage.ExportRowDB) Row {
+	exportRow := Row{
+		NUID:             row.NUID.String,
+		ApplicantName:    row.ApplicantName.String,
+		RegistrationTime: row.RegistrationTime.Time,
+		Submitted:        row.SubmissionTime.Valid,
+		Correct:          row.Correct.Bool,
+		Attempts:         row.Attempts,
+	}
+
+	if row.Score.Valid {
+		exportRow.Score = &row.Score.Int64
+	}
+
+	if row.Percentage.Valid {
+		exportRow.Percentage = &row.Percentage.Float64
+	}
+
+	if row.SubmissionTime.Valid {
+		exportRow.LatestSubmissionTime = &row.SubmissionTime.Time
+		seconds := row.SubmissionTime.Time.Sub(row.RegistrationTime.Time).Seconds()
+		exportRow.TimeToCompletionSeconds = &seconds
+	}
+
+	return exportRow
+}
+
+type Writer interface {
+	Write(row Row) error
+	Flush() error
+}
+
+func NewWriter(format Format, w io.Writer) Writer {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}
+	default:
+		return &csvWriter{writer: csv.NewWriter(w)}
+	}
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+
+	w.headerWritten = true
+
+	return w.writer.Write(header)
+}
+
+func (w *csvWriter) Write(row Row) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.writer.Write([]string{
+		row.NUID,
+		escapeFormula(row.ApplicantName),
+		row.RegistrationTime.Format(time.RFC3339Nano),
+		strconv.FormatBool(row.Submitted),
+		strconv.FormatBool(row.Correct),
+		formatOptionalInt(row.Score),
+		formatOptionalFloat(row.Percentage),
+		strconv.FormatInt(row.Attempts, 10),
+		formatOptionalTime(row.LatestSubmissionTime),
+		formatOptionalFloat(row.TimeToCompletionSeconds),
+	})
+}
+
+func (w *csvWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(row Row) error {
+	return w.encoder.Encode(row)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+func Applicants(adminStorage storage.AdminRepository, cohort domain.CohortName, format Format, w io.Writer) error {
+	writer := NewWriter(format, w)
+
+	err := adminStorage.ExportApplicants(cohort, func(row storage.ExportRowDB) error {
+		return writer.Write(NewRow(row))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func formatOptionalInt(value *int64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(*value, 10)
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ActivityResponse struct {
+	NUID                       domain.NUID       `json:"nuid"`
+	RegistrationTime           time.Time         `json:"registration_time"`
+	Fetches                    int64             `json:"fetches"`
+	FirstFetch                 *time.Time        `json:"first_fetch,omitempty"`
+	LastFetch                  *time.Time        `json:"last_fetch,omitempty"`
+	LastIP                     string            `json:"last_ip,omitempty"`
+	LastUserAgent              string            `json:"last_user_agent,omitempty"`
+	FirstCorrect               *time.Time        `json:"first_correct,omitempty"`
+	RegistrationToFirstCorrect *TimeToCompletion `json:"registration_to_first_correct,omitempty"`
+	FirstFetchToFirstCorrect   *TimeToCompletion `json:"first_fetch_to_first_correct,omitempty"`
+}
+
+func registrationToFirstCorrectDuration(activity storage.ActivityDB) (time.Duration, bool) {
+	if !activity.FirstCorrect.Valid || !activity.RegistrationTime.Valid {
+		return 0, false
+	}
+
+	return activity.FirstCorrect.Time.Sub(activity.RegistrationTime.Time), true
+}
+
+func firstFetchToFirstCorrectDuration(activity storage.ActivityDB) (time.Duration, bool) {
+	if !activity.FirstCorrect.Valid || !activity.FirstFetch.Valid || activity.FirstFetch.Time.After(activity.FirstCorrect.Time) {
+		return 0, false
+	}
+
+	return activity.FirstCorrect.Time.Sub(activity.FirstFetch.Time), true
+}
+
+func optionalTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func processActivityDB(activity storage.ActivityDB) (ActivityResponse, error) {
+	nuid, err := domain.ParseNUID(activity.NUID.String)
+
+	if err != nil {
+		return ActivityResponse{}, err
+	}
+
+	response := ActivityResponse{
+		NUID:             *nuid,
+		RegistrationTime: activity.RegistrationTime.Time,
+		Fetches:          activity.Fetches,
+		FirstFetch:       optionalTime(activity.FirstFetch),
+		LastFetch:        optionalTime(activity.LastFetch),
+		LastIP:           activity.LastIP.String,
+		LastUserAgent:    activity.LastUserAgent.String,
+		FirstCorrect:     optionalTime(activity.FirstCorrect),
+	}
+
+	if duration, ok := registrationToFirstCorrectDuration(activity); ok {
+		completion := convert(duration)
+		response.RegistrationToFirstCorrect = &completion
+	}
+
+	if duration, ok := firstFetchToFirstCorrectDuration(activity); ok {
+		completion := convert(duration)
+		response.FirstFetchToFirstCorrect = &completion
+	}
+
+	return response, nil
+}
+
+func (a *AdminHandler) Activity(c *fiber.Ctx) error {
+	nuid, err := parseNUIDParam(c)
+
+	if err != nil {
+		return err
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := a.Storage.Activity(cohort, *nuid)
+
+	if err != nil {
+		return applicantNotFound(nuid, err)
+	}
+
+	response, err := processActivityDB(result)
+
+	if err != nil {
+		return invalidDatabaseState(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -1,37 +1,57 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
 	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
 	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-type AdminHandler storage.AdminStorage
+type AdminHandler struct {
+	Storage    storage.AdminRepository
+	Cohorts    storage.CohortRepository
+	Challenges *domain.ChallengeRegistry
+	Settings   config.ChallengeSettings
+	Cohort     domain.CohortName
+}
 
-func NewAdminHandler(storage *storage.AdminStorage) *AdminHandler {
-	return (*AdminHandler)(storage)
+func NewAdminHandler(storage storage.AdminRepository, cohorts storage.CohortRepository, challenges *domain.ChallengeRegistry, settings config.Settings) *AdminHandler {
+	return &AdminHandler{Storage: storage, Cohorts: cohorts, Challenges: challenges, Settings: settings.Challenge, Cohort: settings.Application.ActiveCohort()}
 }
 
 type ApplicantResponse struct {
-	NUID             domain.NUID          `json:"nuid"`
-	ApplicantName    domain.ApplicantName `json:"name"`
-	Correct          bool                 `json:"correct"`
-	TimeToCompletion TimeToCompletion     `json:"time_to_completion"`
+	NUID                   domain.NUID          `json:"nuid"`
+	ApplicantName          domain.ApplicantName `json:"name"`
+	Correct                bool                 `json:"correct"`
+	Score                  *int                 `json:"score,omitempty"`
+	Percentage             *float64             `json:"percentage,omitempty"`
+	TimeToCompletion       TimeToCompletion     `json:"time_to_completion"`
+	TimeToLatestSubmission TimeToCompletion     `json:"time_to_latest_submission"`
+	TimeToFirstCorrect     *TimeToCompletion    `json:"time_to_first_correct,omitempty"`
+	AttemptsBeforeSuccess  *int                 `json:"attempts_before_success,omitempty"`
+	Deadline               *time.Time           `json:"deadline,omitempty"`
+	Late                   bool                 `json:"late"`
 }
 
 type TimeToCompletion struct {
-	Seconds int `json:"seconds"`
-	Nanos   int `json:"nanos"`
+	Seconds int    `json:"seconds"`
+	Nanos   int    `json:"nanos"`
+	ISO8601 string `json:"iso8601"`
 }
 
 func convert(t time.Duration) TimeToCompletion {
 	return TimeToCompletion{
-		Seconds: int(t.Seconds()),
-		Nanos:   int(t.Nanoseconds()),
+		Seconds: int(t / time.Second),
+		Nanos:   int(t % time.Second),
+		ISO8601: domain.FormatISO8601Duration(t),
 	}
 }
 
@@ -48,12 +68,37 @@ func processApplicantDB(applicant storage.ApplicantDB) (ApplicantResponse, error
 		return ApplicantResponse{}, err
 	}
 
-	return ApplicantResponse{
-		NUID:             *nuid,
-		ApplicantName:    *applicantName,
-		Correct:          applicant.Correct.Bool,
-		TimeToCompletion: convert(applicant.SubmissionTime.Time.Sub(applicant.RegistrationTime.Time)),
-	}, nil
+	timeToLatestSubmission := convert(timeToCompletion(applicant))
+
+	response := ApplicantResponse{
+		NUID:                   *nuid,
+		ApplicantName:          *applicantName,
+		Correct:                applicant.Correct.Bool,
+		TimeToCompletion:       timeToLatestSubmission,
+		TimeToLatestSubmission: timeToLatestSubmission,
+		Late:                   applicant.Late.Bool,
+	}
+
+	if duration, ok := timeToFirstCorrect(applicant); ok {
+		completion := convert(duration)
+		response.TimeToFirstCorrect = &completion
+	}
+
+	if applicant.AttemptsBeforeSuccess.Valid {
+		attempts := int(applicant.AttemptsBeforeSuccess.Int64)
+		response.AttemptsBeforeSuccess = &attempts
+	}
+
+	if applicant.Score.Valid {
+		score := int(applicant.Score.Int64)
+		response.Score = &score
+	}
+
+	if applicant.Percentage.Valid {
+		response.Percentage = &applicant.Percentage.Float64
+	}
+
+	return response, nil
 }
 
 func (a *AdminHandler) Applicant(c *fiber.Ctx) error {
@@ -62,26 +107,409 @@ func (a *AdminHandler) Applicant(c *fiber.Ctx) error {
 	nuid, err := domain.ParseNUID(rawNUID)
 
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("invalid NUID %s", rawNUID))
+		return NewProblem(fiber.StatusBadRequest, CodeNUIDInvalid, fmt.Sprintf("invalid NUID %s", rawNUID))
 	}
 
-	result, err := (*storage.AdminStorage)(a).Applicant(*nuid)
+	cohort, err := requestCohort(c, a.Cohort)
 
-	if err != nil && !result.NUID.Valid && !result.ApplicantName.Valid && !result.Correct.Valid && !result.SubmissionTime.Valid && !result.RegistrationTime.Valid {
-		return c.Status(fiber.StatusNotFound).SendString(fmt.Sprintf("Applicant with NUID %s not found!", nuid))
+	if err != nil {
+		return err
+	}
+
+	result, err := a.Storage.Applicant(cohort, *nuid)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return NewProblem(fiber.StatusNotFound, CodeApplicantNotFound, fmt.Sprintf("Applicant with NUID %s not found!", nuid))
 	} else if err != nil {
 		return err
 	}
 
 	if !result.Correct.Valid && !result.SubmissionTime.Valid {
-		return c.Status(fiber.StatusOK).SendString(fmt.Sprintf("Applicant with NUID %s has not submitted yet!", nuid))
+		return NewProblem(fiber.StatusNotFound, CodeNotSubmitted, fmt.Sprintf("Applicant with NUID %s has not submitted yet!", nuid))
 	}
 
 	ApplicantResponse, err := processApplicantDB(result)
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).SendString(fmt.Sprintf("invalid database state! Error: %v", err))
+		return invalidDatabaseState(c, err)
+	}
+
+	settings, err := cohortChallengeSettings(a.Cohorts, cohort, a.Settings)
+
+	if err != nil && !errors.Is(err, storage.ErrCohortNotFound) {
+		return err
 	}
 
+	ApplicantResponse.Deadline = challengeDeadline(settings).For(result.RegistrationTime)
+
 	return c.Status(fiber.StatusOK).JSON(ApplicantResponse)
 }
+
+type SubmissionResponse struct {
+	SubmissionID   int64                 `json:"submission_id"`
+	NUID           domain.NUID           `json:"nuid"`
+	Correct        bool                  `json:"correct"`
+	Score          *int                  `json:"score,omitempty"`
+	Percentage     *float64              `json:"percentage,omitempty"`
+	SubmissionTime time.Time             `json:"submission_time"`
+	Submission     []string              `json:"submission"`
+	Solution       []string              `json:"solution"`
+	Diff           []SubmissionDiffEntry `json:"diff,omitempty"`
+	Late           bool                  `json:"late"`
+}
+
+type SubmissionDiffEntry struct {
+	Index    int               `json:"index"`
+	Expected *string           `json:"expected,omitempty"`
+	Given    *string           `json:"given,omitempty"`
+	Result   domain.CaseResult `json:"result"`
+}
+
+func diffSubmission(solution []string, submission []string) []SubmissionDiffEntry {
+	grade := domain.GradeCases(solution, submission)
+
+	diff := make([]SubmissionDiffEntry, len(grade.Results))
+
+	for i, result := range grade.Results {
+		entry := SubmissionDiffEntry{Index: i, Result: result}
+
+		if i < len(solution) {
+			entry.Expected = &solution[i]
+		}
+
+		if i < len(submission) {
+			entry.Given = &submission[i]
+		}
+
+		diff[i] = entry
+	}
+
+	return diff
+}
+
+func processSubmissionDB(submission storage.SubmissionDB) (SubmissionResponse, error) {
+	nuid, err := domain.ParseNUID(submission.NUID.String)
+
+	if err != nil {
+		return SubmissionResponse{}, err
+	}
+
+	response := SubmissionResponse{
+		SubmissionID:   submission.SubmissionID,
+		NUID:           *nuid,
+		Correct:        submission.Correct.Bool,
+		SubmissionTime: submission.SubmissionTime.Time,
+		Submission:     submission.Submission,
+		Solution:       submission.Solution,
+		Late:           submission.Late.Bool,
+	}
+
+	if submission.Score.Valid {
+		score := int(submission.Score.Int64)
+		response.Score = &score
+	}
+
+	if submission.Percentage.Valid {
+		response.Percentage = &submission.Percentage.Float64
+	}
+
+	if submission.Submission != nil {
+		response.Diff = diffSubmission(submission.Solution, submission.Submission)
+	}
+
+	return response, nil
+}
+
+func (a *AdminHandler) Submission(c *fiber.Ctx) error {
+	rawSubmissionID := c.Params("id")
+
+	submissionID, err := c.ParamsInt("id")
+
+	if err != nil || submissionID < 1 {
+		return NewProblem(fiber.StatusBadRequest, CodeSubmissionIDInvalid, fmt.Sprintf("invalid submission ID %s", rawSubmissionID))
+	}
+
+	result, err := a.Storage.Submission(int64(submissionID))
+
+	if errors.Is(err, storage.ErrNotFound) {
+		return NewProblem(fiber.StatusNotFound, CodeSubmissionNotFound, fmt.Sprintf("Submission with ID %d not found!", submissionID))
+	} else if err != nil {
+		return err
+	}
+
+	submissionResponse, err := processSubmissionDB(result)
+
+	if err != nil {
+		return invalidDatabaseState(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(submissionResponse)
+}
+
+func (a *AdminHandler) Submissions(c *fiber.Ctx) error {
+	rawNUID := c.Params("nuid")
+
+	nuid, err := domain.ParseNUID(rawNUID)
+
+	if err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeNUIDInvalid, fmt.Sprintf("invalid NUID %s", rawNUID))
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	results, err := a.Storage.Submissions(cohort, *nuid)
+
+	if err != nil {
+		return err
+	}
+
+	submissionResponses := make([]SubmissionResponse, len(results))
+
+	for i, result := range results {
+		submissionResponse, err := processSubmissionDB(result)
+
+		if err != nil {
+			return invalidDatabaseState(c, err)
+		}
+
+		submissionResponses[i] = submissionResponse
+	}
+
+	return c.Status(fiber.StatusOK).JSON(submissionResponses)
+}
+
+const (
+	defaultApplicantsLimit = 50
+	maxApplicantsLimit     = 500
+)
+
+type ApplicantsResponse struct {
+	Applicants []ApplicantListItem `json:"applicants"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type ApplicantListItem struct {
+	NUID             domain.NUID          `json:"nuid"`
+	ApplicantName    domain.ApplicantName `json:"name"`
+	RegistrationTime time.Time            `json:"registration_time"`
+	Submitted        bool                 `json:"submitted"`
+	Correct          bool                 `json:"correct"`
+	Score            *int                 `json:"score,omitempty"`
+	Percentage       *float64             `json:"percentage,omitempty"`
+	TimeToCompletion *TimeToCompletion    `json:"time_to_completion,omitempty"`
+	Late             bool                 `json:"late"`
+}
+
+func encodeApplicantCursor(cursor storage.ApplicantCursor) string {
+	raw := fmt.Sprintf("%s,%s", strconv.FormatFloat(cursor.SortKey, 'g', -1, 64), cursor.NUID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeApplicantCursor(str string) (*storage.ApplicantCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(str)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor! Given: %s", str)
+	}
+
+	parts := strings.SplitN(string(raw), ",", 2)
+
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor! Given: %s", str)
+	}
+
+	sortKey, err := strconv.ParseFloat(parts[0], 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor! Given: %s", str)
+	}
+
+	nuid, err := domain.ParseNUID(parts[1])
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor! Given: %s", str)
+	}
+
+	return &storage.ApplicantCursor{SortKey: sortKey, NUID: nuid.String()}, nil
+}
+
+func parseOptionalBool(c *fiber.Ctx, key string) (*bool, error) {
+	raw := c.Query(key)
+
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %s", key, raw)
+	}
+
+	return &value, nil
+}
+
+func parseApplicantsQuery(c *fiber.Ctx, cohort domain.CohortName) (storage.ApplicantsQuery, error) {
+	query := storage.ApplicantsQuery{
+		Cohort: cohort,
+		Sort:   storage.SortRegistrationTime,
+		Limit:  defaultApplicantsLimit,
+	}
+
+	correct, err := parseOptionalBool(c, "correct")
+
+	if err != nil {
+		return query, err
+	}
+
+	query.Filter.Correct = correct
+
+	submitted, err := parseOptionalBool(c, "submitted")
+
+	if err != nil {
+		return query, err
+	}
+
+	query.Filter.Submitted = submitted
+
+	if rawRegisteredAfter := c.Query("registered_after"); rawRegisteredAfter != "" {
+		registeredAfter, err := time.Parse(time.RFC3339, rawRegisteredAfter)
+
+		if err != nil {
+			return query, fmt.Errorf("invalid registered_after %s", rawRegisteredAfter)
+		}
+
+		query.Filter.RegisteredAfter = &registeredAfter
+	}
+
+	if rawSort := c.Query("sort"); rawSort != "" {
+		sort, err := storage.ParseApplicantSort(rawSort)
+
+		if err != nil {
+			return query, fmt.Errorf("invalid sort %s", rawSort)
+		}
+
+		query.Sort = sort
+	}
+
+	switch order := c.Query("order", "asc"); order {
+	case "asc":
+		query.Descending = false
+	case "desc":
+		query.Descending = true
+	default:
+		return query, fmt.Errorf("invalid order %s", order)
+	}
+
+	if rawLimit := c.Query("limit"); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+
+		if err != nil || limit < 1 || limit > maxApplicantsLimit {
+			return query, fmt.Errorf("invalid limit %s", rawLimit)
+		}
+
+		query.Limit = limit
+	}
+
+	if rawCursor := c.Query("cursor"); rawCursor != "" {
+		cursor, err := decodeApplicantCursor(rawCursor)
+
+		if err != nil {
+			return query, fmt.Errorf("invalid cursor %s", rawCursor)
+		}
+
+		query.After = cursor
+	}
+
+	return query, nil
+}
+
+func processApplicantPageDB(applicant storage.ApplicantPageDB) (ApplicantListItem, error) {
+	nuid, err := domain.ParseNUID(applicant.NUID.String)
+
+	if err != nil {
+		return ApplicantListItem{}, err
+	}
+
+	applicantName, err := domain.ParseApplicantName(applicant.ApplicantName.String)
+
+	if err != nil {
+		return ApplicantListItem{}, err
+	}
+
+	item := ApplicantListItem{
+		NUID:             *nuid,
+		ApplicantName:    *applicantName,
+		RegistrationTime: applicant.RegistrationTime.Time,
+		Submitted:        applicant.SubmissionTime.Valid,
+		Correct:          applicant.Correct.Bool,
+		Late:             applicant.Late.Bool,
+	}
+
+	if applicant.Score.Valid {
+		score := int(applicant.Score.Int64)
+		item.Score = &score
+	}
+
+	if applicant.Percentage.Valid {
+		item.Percentage = &applicant.Percentage.Float64
+	}
+
+	if applicant.SubmissionTime.Valid {
+		completion := convert(timeToCompletion(applicant.ApplicantDB))
+		item.TimeToCompletion = &completion
+	}
+
+	return item, nil
+}
+
+func (a *AdminHandler) Applicants(c *fiber.Ctx) error {
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	query, err := parseApplicantsQuery(c, cohort)
+
+	if err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeQueryInvalid, err.Error())
+	}
+
+	limit := query.Limit
+	query.Limit = limit + 1
+
+	results, err := a.Storage.Applicants(query)
+
+	if err != nil {
+		return err
+	}
+
+	var nextCursor string
+	if len(results) > limit {
+		results = results[:limit]
+		last := results[limit-1]
+		nextCursor = encodeApplicantCursor(storage.ApplicantCursor{SortKey: last.SortKey, NUID: last.NUID.String})
+	}
+
+	applicants := make([]ApplicantListItem, len(results))
+
+	for i, result := range results {
+		item, err := processApplicantPageDB(result)
+
+		if err != nil {
+			return invalidDatabaseState(c, err)
+		}
+
+		applicants[i] = item
+	}
+
+	return c.Status(fiber.StatusOK).JSON(ApplicantsResponse{
+		Applicants: applicants,
+		NextCursor: nextCursor,
+	})
+}
@@ -1,24 +1,40 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
 	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/mailer"
 	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
-type ApplicantHandler storage.ApplicantStorage
+type ApplicantHandler struct {
+	Storage  storage.ApplicantRepository
+	Cohorts  storage.CohortRepository
+	Mailer   mailer.Mailer
+	Settings config.ChallengeSettings
+	Cohort   domain.CohortName
+	BaseUrl  string
+	IPHeader string
+}
 
-func NewApplicantHandler(storage *storage.ApplicantStorage) *ApplicantHandler {
-	return (*ApplicantHandler)(storage)
+func NewApplicantHandler(storage storage.ApplicantRepository, cohorts storage.CohortRepository, mailer mailer.Mailer, settings config.Settings) *ApplicantHandler {
+	return &ApplicantHandler{Storage: storage, Cohorts: cohorts, Mailer: mailer, Settings: settings.Challenge, Cohort: settings.Application.ActiveCohort(), BaseUrl: settings.Application.BaseUrl, IPHeader: settings.Application.RateLimit.IPHeader}
 }
 
 type RegisterRequestBody struct {
 	RawApplicantName string `json:"name"`
 	RawNUID          string `json:"nuid"`
+	RawEmail         string `json:"email"`
 }
 
 type RegisterResponse struct {
@@ -30,40 +46,60 @@ func (a *ApplicantHandler) Register(c *fiber.Ctx) error {
 	var registerRequestBody RegisterRequestBody
 
 	if err := c.BodyParser(&registerRequestBody); err != nil {
-		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("invalid request body %s", registerRequestBody))
+		return NewProblem(fiber.StatusBadRequest, CodeRequestBodyInvalid, "invalid request body")
 	}
 
 	nuid, err := domain.ParseNUID(registerRequestBody.RawNUID)
 
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("invalid NUID %s", registerRequestBody.RawNUID))
+		return NewProblem(fiber.StatusBadRequest, CodeNUIDInvalid, fmt.Sprintf("invalid NUID %s", registerRequestBody.RawNUID))
 	}
 
 	applicantName, err := domain.ParseApplicantName(registerRequestBody.RawApplicantName)
 
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("invalid applicant name %s", registerRequestBody.RawApplicantName))
+		return NewProblem(fiber.StatusBadRequest, CodeApplicantNameInvalid, fmt.Sprintf("invalid applicant name %s", registerRequestBody.RawApplicantName))
 	}
 
-	result, err := (*storage.ApplicantStorage)(a).Register(domain.Applicant{
-		NUID: *nuid,
-		Name: *applicantName,
-	})
+	email, err := domain.ParseEmail(registerRequestBody.RawEmail)
 
 	if err != nil {
-		pgErr, isPGError := err.(*pq.Error)
-		if isPGError && pgErr.Code == "23505" {
-			return c.Status(fiber.StatusConflict).SendString(fmt.Sprintf("NUID %s has already registered! Use the forgot_token endpoint to retrieve your token.", nuid))
-		} else {
-			return err
-		}
+		return NewProblem(fiber.StatusBadRequest, CodeEmailInvalid, fmt.Sprintf("invalid email %s", registerRequestBody.RawEmail))
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := a.Storage.Register(domain.Applicant{
+		Cohort: cohort,
+		NUID:   *nuid,
+		Name:   *applicantName,
+		Email:  *email,
+	})
+
+	if errors.Is(err, storage.ErrAlreadyRegistered) {
+		return NewProblem(fiber.StatusConflict, CodeAlreadyRegistered, fmt.Sprintf("NUID %s has already registered! Use the forgot_token endpoint to retrieve your token.", nuid))
+	} else if err != nil {
+		return cohortProblem(cohort, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(result)
 }
 
 type ForgotTokenResponse struct {
-	Token uuid.UUID `json:"token"`
+	Message string `json:"message"`
+}
+
+func (a *ApplicantHandler) tokenRecoveryMessage(result storage.ForgotTokenDB) mailer.Message {
+	return mailer.Message{
+		To:      result.Email.String,
+		Subject: "Your coding challenge token",
+		Body: fmt.Sprintf("Hi %s,\n\nYour coding challenge token is %s.\n\nYou can fetch your challenge at %s/v1/challenge/%s.\n",
+			result.ApplicantName.String, result.Token.String, a.BaseUrl, result.Token.String),
+	}
 }
 
 func (a *ApplicantHandler) ForgotToken(c *fiber.Ctx) error {
@@ -71,28 +107,36 @@ func (a *ApplicantHandler) ForgotToken(c *fiber.Ctx) error {
 
 	nuid, err := domain.ParseNUID(rawNUID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("invalid NUID %s", rawNUID))
+		return NewProblem(fiber.StatusBadRequest, CodeNUIDInvalid, fmt.Sprintf("invalid NUID %s", rawNUID))
 	}
 
-	result, err := (*storage.ApplicantStorage)(a).ForgotToken(*nuid)
+	cohort, err := requestCohort(c, a.Cohort)
 
-	if err != nil && !result.Token.Valid {
-		return c.Status(fiber.StatusNotFound).SendString(fmt.Sprintf("Applicant with NUID %s not found!", nuid))
-	} else if err != nil {
+	if err != nil {
 		return err
 	}
 
-	token, err := uuid.Parse(result.Token.String)
+	result, err := a.Storage.ForgotToken(cohort, *nuid)
 
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).SendString(fmt.Sprintf("invalid database state! Error: %v", err))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(ForgotTokenResponse{
-		Token: token,
+	if err == nil && result.Email.Valid {
+		if err := a.Mailer.Send(a.tokenRecoveryMessage(result)); err != nil {
+			log.Errorf("failed to send token recovery email for NUID %s: %v", nuid, err)
+		}
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(ForgotTokenResponse{
+		Message: "If an applicant with that NUID has registered, their token has been emailed to the address they registered with.",
 	})
 }
 
+func tokenRevokedProblem(token uuid.UUID) *Problem {
+	return NewProblem(fiber.StatusForbidden, CodeTokenRevoked, fmt.Sprintf("Token %s has been revoked! Contact the recruiting team for a new one.", token))
+}
+
 type ChallengeResponse struct {
 	Challenge []string `json:"challenge"`
 }
@@ -102,13 +146,18 @@ func (a *ApplicantHandler) Challenge(c *fiber.Ctx) error {
 
 	token, err := uuid.Parse(rawToken)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("invalid token %s", rawToken))
+		return NewProblem(fiber.StatusBadRequest, CodeTokenInvalid, fmt.Sprintf("invalid token %s", rawToken))
 	}
 
-	result, err := (*storage.ApplicantStorage)(a).Challenge(token)
+	result, err := a.Storage.Challenge(token, storage.ChallengeFetch{
+		IP:        strings.Clone(clientIP(c, a.IPHeader)),
+		UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+	})
 
-	if err != nil && len(result.Challenge) == 0 {
-		return c.Status(fiber.StatusNotFound).SendString(fmt.Sprintf("Record associated with token %s not found!", token))
+	if errors.Is(err, storage.ErrNotFound) {
+		return NewProblem(fiber.StatusNotFound, CodeTokenNotFound, fmt.Sprintf("Record associated with token %s not found!", token))
+	} else if errors.Is(err, storage.ErrTokenRevoked) {
+		return tokenRevokedProblem(token)
 	} else if err != nil {
 		return err
 	}
@@ -121,8 +170,31 @@ func (a *ApplicantHandler) Challenge(c *fiber.Ctx) error {
 type SubmitRequestBody []string
 
 type SubmitResponseBody struct {
-	Correct bool   `json:"correct"`
-	Message string `json:"message"`
+	Correct           bool   `json:"correct"`
+	Message           string `json:"message"`
+	NumCorrect        *int   `json:"num_correct,omitempty"`
+	RemainingAttempts *int   `json:"remaining_attempts,omitempty"`
+	Late              bool   `json:"late,omitempty"`
+}
+
+func challengeDeadline(settings config.ChallengeSettings) storage.Deadline {
+	return storage.Deadline{
+		CloseTime:  settings.CloseAt(),
+		TimeBudget: settings.TimeBudget,
+		RejectLate: settings.LateSubmissions != config.LateSubmissionsFlag,
+	}
+}
+
+func submissionPolicy(settings config.ChallengeSettings) storage.SubmissionPolicy {
+	return storage.SubmissionPolicy{
+		MaxAttempts:        settings.MaxAttempts,
+		MinAttemptInterval: settings.MinAttemptInterval,
+		Deadline:           challengeDeadline(settings),
+	}
+}
+
+func retryAfterSeconds(retryAfter time.Duration) int {
+	return int(math.Ceil(retryAfter.Seconds()))
 }
 
 func (a *ApplicantHandler) Submit(c *fiber.Ctx) error {
@@ -131,50 +203,80 @@ func (a *ApplicantHandler) Submit(c *fiber.Ctx) error {
 	token, err := uuid.Parse(rawToken)
 
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid token %s", rawToken))
+		return NewProblem(fiber.StatusBadRequest, CodeTokenInvalid, fmt.Sprintf("invalid token %s", rawToken))
 	}
 
 	var submitRequestBody SubmitRequestBody
 
 	if err := c.BodyParser(&submitRequestBody); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid request body %s", submitRequestBody))
+		return NewProblem(fiber.StatusBadRequest, CodeRequestBodyInvalid, "invalid request body")
 	}
 
-	result, err := (*storage.ApplicantStorage)(a).Submit(token, submitRequestBody)
+	result, err := a.Storage.Submit(token, submitRequestBody)
 
-	if err != nil {
+	if errors.Is(err, storage.ErrNotFound) {
+		return NewProblem(fiber.StatusNotFound, CodeTokenNotFound, fmt.Sprintf("Record associated with token %s not found!", token))
+	} else if errors.Is(err, storage.ErrTokenRevoked) {
+		return tokenRevokedProblem(token)
+	} else if err != nil {
 		return err
 	}
 
-	if !result.NUID.Valid && len(result.Solution) == 0 {
-		return c.Status(fiber.StatusNotFound).SendString(fmt.Sprintf("Record associated with token %s not found!", token))
-	}
-
 	nuid, err := domain.ParseNUID(result.NUID.String)
 
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).SendString(fmt.Sprintf("invalid database state! Error: %v", err))
+		return invalidDatabaseState(c, err)
 	}
 
-	correct, err := (*storage.ApplicantStorage)(a).WriteSubmit(*nuid, result.Solution, submitRequestBody)
+	settings, err := cohortChallengeSettings(a.Cohorts, domain.CohortName(result.Cohort.String), a.Settings)
 
 	if err != nil {
 		return err
 	}
 
+	outcome, err := a.Storage.WriteSubmit(*nuid, result, submitRequestBody, submissionPolicy(settings))
+
+	var lateErr *storage.LateSubmissionError
+	if errors.As(err, &lateErr) {
+		return NewProblem(fiber.StatusForbidden, CodeDeadlinePassed, fmt.Sprintf("The deadline for this challenge passed at %s!", lateErr.Deadline.Format(time.RFC3339)))
+	}
+
+	var limitErr *storage.AttemptLimitError
+	if errors.As(err, &limitErr) {
+		if limitErr.Exhausted {
+			return NewProblem(fiber.StatusTooManyRequests, CodeAttemptsExhausted, fmt.Sprintf("No submission attempts remaining! The maximum is %d.", settings.MaxAttempts))
+		}
+
+		retryAfter := retryAfterSeconds(limitErr.RetryAfter)
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+		return NewProblem(fiber.StatusTooManyRequests, CodeSubmissionCooldown, fmt.Sprintf("Submitted too soon! Try again in %d seconds.", retryAfter))
+	} else if err != nil {
+		return err
+	}
+
+	grade := outcome.Grade
+
 	var response SubmitResponseBody
 
-	if correct {
+	if grade.Correct() {
 		response = SubmitResponseBody{
-			Correct: correct,
+			Correct: true,
 			Message: "Correct - nice work!",
 		}
 	} else {
 		response = SubmitResponseBody{
-			Correct: correct,
+			Correct: false,
 			Message: "Incorrect Solution",
 		}
 	}
 
+	if settings.RevealScore {
+		response.NumCorrect = &grade.Score
+	}
+
+	response.RemainingAttempts = outcome.RemainingAttempts
+	response.Late = outcome.Late
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
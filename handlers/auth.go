@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const adminAuditEntryKey = "admin_audit_entry"
+
+type AuthHandler struct {
+	Storage  storage.APIKeyRepository
+	Settings config.AdminSettings
+	Admin    fiber.Handler
+}
+
+func NewAuthHandler(storage storage.APIKeyRepository, settings config.Settings) *AuthHandler {
+	a := &AuthHandler{Storage: storage, Settings: settings.Admin}
+
+	a.Admin = keyauth.New(keyauth.Config{
+		KeyLookup:      "header:" + fiber.HeaderAuthorization,
+		AuthScheme:     "Bearer",
+		Validator:      a.validate,
+		SuccessHandler: a.audit,
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+
+			if errors.Is(err, keyauth.ErrMissingOrMalformedAPIKey) {
+				return NewProblem(fiber.StatusUnauthorized, CodeAdminCredentialsMissing, "missing admin credentials")
+			} else if err != nil {
+				return err
+			}
+
+			return NewProblem(fiber.StatusUnauthorized, CodeAdminCredentialsInvalid, "invalid admin credentials")
+		},
+	})
+
+	return a
+}
+
+func (a *AuthHandler) validate(c *fiber.Ctx, credential string) (bool, error) {
+	entry := storage.AdminAuditEntry{
+		Method:      utils.CopyString(c.Method()),
+		Path:        utils.CopyString(c.Path()),
+		RequestTime: time.Now(),
+	}
+
+	if domain.IsAPIKey(credential) {
+		apiKey, err := a.Storage.Authenticate(credential)
+
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+
+		entry.APIKeyID = &apiKey.APIKeyID
+		entry.Subject = "api_key:" + apiKey.Name
+	} else if a.Settings.TokenSecret != "" {
+		claims, err := domain.VerifyAdminToken([]byte(a.Settings.TokenSecret), credential, time.Now())
+
+		if err != nil {
+			return false, nil
+		}
+
+		entry.Subject = "token:" + claims.Subject
+	} else {
+		return false, nil
+	}
+
+	c.Locals(adminAuditEntryKey, entry)
+
+	return true, nil
+}
+
+func adminSubject(c *fiber.Ctx) string {
+	entry, ok := c.Locals(adminAuditEntryKey).(storage.AdminAuditEntry)
+
+	if !ok {
+		return ""
+	}
+
+	return entry.Subject
+}
+
+func (a *AuthHandler) audit(c *fiber.Ctx) error {
+	err := c.Next()
+
+	entry := c.Locals(adminAuditEntryKey).(storage.AdminAuditEntry)
+	entry.Status = c.Response().StatusCode()
+
+	if err != nil {
+		entry.Status = problemFromError(err).Status
+	}
+
+	if auditErr := a.Storage.RecordAudit(entry); auditErr != nil {
+		log.Errorf("failed to record admin audit entry for %s: %v", entry.Subject, auditErr)
+	}
+
+	return err
+}
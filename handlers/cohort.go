@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CohortResponse struct {
+	Name             domain.CohortName      `json:"name"`
+	Open             bool                   `json:"open"`
+	OpensAt          time.Time              `json:"opens_at"`
+	ClosesAt         *time.Time             `json:"closes_at,omitempty"`
+	ClosedAt         *time.Time             `json:"closed_at,omitempty"`
+	ChallengeType    string                 `json:"challenge_type"`
+	ChallengeVersion int                    `json:"challenge_version"`
+	Settings         storage.CohortSettings `json:"settings"`
+	CreatedAt        time.Time              `json:"created_at"`
+}
+
+type CreateCohortRequestBody struct {
+	RawName          string                 `json:"name"`
+	OpensAt          *time.Time             `json:"opens_at"`
+	ClosesAt         *time.Time             `json:"closes_at"`
+	ChallengeType    string                 `json:"challenge_type"`
+	ChallengeVersion int                    `json:"challenge_version"`
+	Settings         storage.CohortSettings `json:"settings"`
+}
+
+type CohortComparison struct {
+	Cohort                    domain.CohortName `json:"cohort"`
+	ChallengeType             string            `json:"challenge_type"`
+	ChallengeVersion          int               `json:"challenge_version"`
+	Registrations             int64             `json:"registrations"`
+	Submissions               int64             `json:"submissions"`
+	SubmittedApplicants       int64             `json:"submitted_applicants"`
+	CorrectApplicants         int64             `json:"correct_applicants"`
+	PassRate                  float64           `json:"pass_rate"`
+	MedianTimeToFirstCorrect  *TimeToCompletion `json:"median_time_to_first_correct,omitempty"`
+	MeanAttemptsBeforeSuccess *float64          `json:"mean_attempts_before_success,omitempty"`
+}
+
+func requestCohort(c *fiber.Ctx, active domain.CohortName) (domain.CohortName, error) {
+	rawCohort := c.Query("cohort")
+
+	if rawCohort == "" {
+		return active, nil
+	}
+
+	cohort, err := domain.ParseCohortName(strings.Clone(rawCohort))
+
+	if err != nil {
+		return "", NewProblem(fiber.StatusBadRequest, CodeCohortInvalid, fmt.Sprintf("invalid cohort %s", rawCohort))
+	}
+
+	return *cohort, nil
+}
+
+func cohortProblem(cohort domain.CohortName, err error) error {
+	if errors.Is(err, storage.ErrCohortNotFound) {
+		return NewProblem(fiber.StatusNotFound, CodeCohortNotFound, fmt.Sprintf("Cohort %s not found!", cohort))
+	} else if errors.Is(err, storage.ErrCohortClosed) {
+		return NewProblem(fiber.StatusForbidden, CodeCohortClosed, fmt.Sprintf("Cohort %s is not open for registration!", cohort))
+	}
+
+	return err
+}
+
+func cohortChallengeSettings(cohorts storage.CohortRepository, cohort domain.CohortName, settings config.ChallengeSettings) (config.ChallengeSettings, error) {
+	result, err := cohorts.Cohort(cohort)
+
+	if err != nil {
+		return settings, err
+	}
+
+	return result.Settings.Apply(settings)
+}
+
+func processCohortDB(cohort storage.CohortDB, now time.Time) CohortResponse {
+	return CohortResponse{
+		Name:             domain.CohortName(cohort.Name),
+		Open:             cohort.OpenAt(now),
+		OpensAt:          cohort.OpensAt,
+		ClosesAt:         optionalTime(cohort.ClosesAt),
+		ClosedAt:         optionalTime(cohort.ClosedAt),
+		ChallengeType:    cohort.ChallengeType,
+		ChallengeVersion: cohort.ChallengeVersion,
+		Settings:         cohort.Settings,
+		CreatedAt:        cohort.CreatedAt,
+	}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func (a *AdminHandler) CreateCohort(c *fiber.Ctx) error {
+	var body CreateCohortRequestBody
+
+	if err := c.BodyParser(&body); err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeRequestBodyInvalid, "invalid request body")
+	}
+
+	name, err := domain.ParseCohortName(body.RawName)
+
+	if err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeCohortInvalid, fmt.Sprintf("invalid cohort %s", body.RawName))
+	}
+
+	if body.ChallengeType == "" {
+		body.ChallengeType = a.Settings.Type
+		body.ChallengeVersion = a.Settings.Version
+	}
+
+	generator, err := a.Challenges.Resolve(body.ChallengeType, body.ChallengeVersion)
+
+	if err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeRequestBodyInvalid, err.Error())
+	}
+
+	if _, err := body.Settings.Apply(a.Settings); err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeRequestBodyInvalid, err.Error())
+	}
+
+	createdAt := time.Now()
+	opensAt := createdAt
+
+	if body.OpensAt != nil {
+		opensAt = *body.OpensAt
+	}
+
+	if body.ClosesAt != nil && !body.ClosesAt.After(opensAt) {
+		return NewProblem(fiber.StatusBadRequest, CodeRequestBodyInvalid, "closes_at must be after opens_at")
+	}
+
+	cohort := storage.CohortDB{
+		Name:             name.String(),
+		OpensAt:          opensAt,
+		ClosesAt:         nullTime(body.ClosesAt),
+		ChallengeType:    generator.Name(),
+		ChallengeVersion: generator.Version(),
+		Settings:         body.Settings,
+		CreatedAt:        createdAt,
+	}
+
+	if err := a.Cohorts.CreateCohort(cohort); errors.Is(err, storage.ErrCohortExists) {
+		return NewProblem(fiber.StatusConflict, CodeCohortExists, fmt.Sprintf("Cohort %s already exists!", name))
+	} else if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(processCohortDB(cohort, createdAt))
+}
+
+func (a *AdminHandler) ListCohorts(c *fiber.Ctx) error {
+	cohorts, err := a.Cohorts.Cohorts()
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	responses := make([]CohortResponse, len(cohorts))
+
+	for i, cohort := range cohorts {
+		responses[i] = processCohortDB(cohort, now)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses)
+}
+
+func (a *AdminHandler) CloseCohort(c *fiber.Ctx) error {
+	rawName := c.Params("name")
+
+	name, err := domain.ParseCohortName(rawName)
+
+	if err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeCohortInvalid, fmt.Sprintf("invalid cohort %s", rawName))
+	}
+
+	now := time.Now()
+	cohort, err := a.Cohorts.CloseCohort(*name, now)
+
+	if err != nil {
+		return cohortProblem(*name, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(processCohortDB(cohort, now))
+}
+
+func (a *AdminHandler) compareCohort(cohort storage.CohortDB) (CohortComparison, error) {
+	name := domain.CohortName(cohort.Name)
+
+	counts, err := a.Storage.Counts(name)
+
+	if err != nil {
+		return CohortComparison{}, err
+	}
+
+	correctApplicants, err := a.Storage.CorrectApplicants(name)
+
+	if err != nil {
+		return CohortComparison{}, err
+	}
+
+	comparison := CohortComparison{
+		Cohort:              name,
+		ChallengeType:       cohort.ChallengeType,
+		ChallengeVersion:    cohort.ChallengeVersion,
+		Registrations:       counts.Registrations,
+		Submissions:         counts.Submissions,
+		SubmittedApplicants: counts.SubmittedApplicants,
+		CorrectApplicants:   counts.CorrectApplicants,
+	}
+
+	if counts.SubmittedApplicants > 0 {
+		comparison.PassRate = float64(counts.CorrectApplicants) / float64(counts.SubmittedApplicants)
+	}
+
+	var durations []time.Duration
+	var attempts, successes int64
+
+	for _, applicant := range correctApplicants {
+		if duration, ok := timeToFirstCorrect(applicant); ok {
+			durations = append(durations, duration)
+		}
+
+		if applicant.AttemptsBeforeSuccess.Valid {
+			attempts += applicant.AttemptsBeforeSuccess.Int64
+			successes++
+		}
+	}
+
+	if len(durations) > 0 {
+		median := convert(domain.Percentile(sortedDurations(durations), 50))
+		comparison.MedianTimeToFirstCorrect = &median
+	}
+
+	if successes > 0 {
+		mean := float64(attempts) / float64(successes)
+		comparison.MeanAttemptsBeforeSuccess = &mean
+	}
+
+	return comparison, nil
+}
+
+func (a *AdminHandler) CompareCohorts(c *fiber.Ctx) error {
+	cohorts, err := a.Cohorts.Cohorts()
+
+	if err != nil {
+		return err
+	}
+
+	comparisons := make([]CohortComparison, len(cohorts))
+
+	for i, cohort := range cohorts {
+		comparison, err := a.compareCohort(cohort)
+
+		if err != nil {
+			return err
+		}
+
+		comparisons[i] = comparison
+	}
+
+	return c.Status(fiber.StatusOK).JSON(comparisons)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/export"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+func (a *AdminHandler) Export(c *fiber.Ctx) error {
+	rawFormat := c.Query("format", string(export.FormatCSV))
+
+	format, err := export.ParseFormat(rawFormat)
+
+	if err != nil {
+		return NewProblem(fiber.StatusBadRequest, CodeQueryInvalid, fmt.Sprintf("invalid format %s", rawFormat))
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"applicants.%s\"", format))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := export.Applicants(a.Storage, cohort, format, w); err != nil {
+			log.Errorf("failed to export applicants: %v", err)
+		}
+
+		if err := w.Flush(); err != nil {
+			log.Errorf("failed to flush applicant export: %v", err)
+		}
+	})
+
+	return nil
+}
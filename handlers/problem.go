@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+type ProblemCode string
+
+const (
+	CodeRequestBodyInvalid      ProblemCode = "request_body_invalid"
+	CodeQueryInvalid            ProblemCode = "query_invalid"
+	CodeNUIDInvalid             ProblemCode = "nuid_invalid"
+	CodeApplicantNameInvalid    ProblemCode = "applicant_name_invalid"
+	CodeEmailInvalid            ProblemCode = "email_invalid"
+	CodeAlreadyRegistered       ProblemCode = "already_registered"
+	CodeApplicantNotFound       ProblemCode = "applicant_not_found"
+	CodeNotSubmitted            ProblemCode = "not_submitted"
+	CodeCohortInvalid           ProblemCode = "cohort_invalid"
+	CodeCohortNotFound          ProblemCode = "cohort_not_found"
+	CodeCohortClosed            ProblemCode = "cohort_closed"
+	CodeCohortExists            ProblemCode = "cohort_exists"
+	CodeTokenInvalid            ProblemCode = "token_invalid"
+	CodeTokenNotFound           ProblemCode = "token_not_found"
+	CodeTokenRevoked            ProblemCode = "token_revoked"
+	CodeDeadlinePassed          ProblemCode = "deadline_passed"
+	CodeAttemptsExhausted       ProblemCode = "attempts_exhausted"
+	CodeSubmissionCooldown      ProblemCode = "submission_cooldown"
+	CodeRateLimited             ProblemCode = "rate_limited"
+	CodeSubmissionIDInvalid     ProblemCode = "submission_id_invalid"
+	CodeSubmissionNotFound      ProblemCode = "submission_not_found"
+	CodeAdminCredentialsMissing ProblemCode = "admin_credentials_missing"
+	CodeAdminCredentialsInvalid ProblemCode = "admin_credentials_invalid"
+	CodeRouteNotFound           ProblemCode = "route_not_found"
+	CodeMethodNotAllowed        ProblemCode = "method_not_allowed"
+	CodeInvalidDatabaseState    ProblemCode = "invalid_database_state"
+	CodeInternalError           ProblemCode = "internal_error"
+)
+
+type Problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty"`
+	Code     ProblemCode `json:"code"`
+}
+
+func NewProblem(status int, code ProblemCode, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  utils.StatusMessage(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func (p *Problem) Error() string {
+	return p.Detail
+}
+
+func invalidDatabaseState(c *fiber.Ctx, err error) *Problem {
+	log.Errorf("invalid database state on %s %s: %v", c.Method(), c.Path(), err)
+
+	return NewProblem(fiber.StatusInternalServerError, CodeInvalidDatabaseState, "invalid database state")
+}
+
+func codeForStatus(status int) ProblemCode {
+	switch status {
+	case fiber.StatusNotFound:
+		return CodeRouteNotFound
+	case fiber.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case fiber.StatusBadRequest:
+		return CodeRequestBodyInvalid
+	default:
+		return CodeInternalError
+	}
+}
+
+func problemFromError(err error) *Problem {
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return NewProblem(fiberErr.Code, codeForStatus(fiberErr.Code), fiberErr.Message)
+	}
+
+	return NewProblem(fiber.StatusInternalServerError, CodeInternalError, "an unexpected error occurred")
+}
+
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	problem := problemFromError(err)
+
+	if problem.Code == CodeInternalError {
+		log.Errorf("unhandled error on %s %s: %v", c.Method(), c.Path(), err)
+	}
+
+	response := *problem
+	response.Instance = c.Path()
+
+	if err := c.Status(response.Status).JSON(response); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, MIMEApplicationProblemJSON)
+
+	return nil
+}
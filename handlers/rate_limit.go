@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type RateLimitHandler struct {
+	Store    ratelimit.Store
+	Settings config.RateLimitSettings
+	Tokens   domain.TokenHasher
+}
+
+func NewRateLimitHandler(store ratelimit.Store, settings config.Settings) *RateLimitHandler {
+	return &RateLimitHandler{Store: store, Settings: settings.Application.RateLimit, Tokens: domain.NewTokenHasher(settings.Application.TokenHashKey)}
+}
+
+func ParamKey(param string) func(*fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		return c.Params(param)
+	}
+}
+
+func (r *RateLimitHandler) TokenKey(param string) func(*fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		if token := c.Params(param); token != "" {
+			return r.Tokens.Hash(token)
+		}
+
+		return ""
+	}
+}
+
+func RegisterKey(c *fiber.Ctx) string {
+	var registerRequestBody RegisterRequestBody
+
+	if err := c.BodyParser(&registerRequestBody); err != nil {
+		return ""
+	}
+
+	return registerRequestBody.RawNUID
+}
+
+func clientIP(c *fiber.Ctx, ipHeader string) string {
+	if ipHeader != "" {
+		if ip := c.Get(ipHeader); ip != "" {
+			return ip
+		}
+	}
+
+	return c.IP()
+}
+
+type rateLimitCheck struct {
+	key   string
+	limit ratelimit.Limit
+}
+
+func (r *RateLimitHandler) Limit(route string, key func(*fiber.Ctx) string) fiber.Handler {
+	settings := r.Settings.Routes[route]
+	perIP := ratelimit.NewLimit(settings.PerIP)
+	perKey := ratelimit.NewLimit(settings.PerKey)
+
+	return func(c *fiber.Ctx) error {
+		var checks []rateLimitCheck
+
+		if perIP.Enabled() {
+			checks = append(checks, rateLimitCheck{fmt.Sprintf("%s:ip:%s", route, clientIP(c, r.Settings.IPHeader)), perIP})
+		}
+
+		if perKey.Enabled() {
+			if k := key(c); k != "" {
+				checks = append(checks, rateLimitCheck{fmt.Sprintf("%s:key:%s", route, k), perKey})
+			}
+		}
+
+		for _, check := range checks {
+			result, err := r.Store.Take(check.key, check.limit)
+
+			if err != nil {
+				log.Errorf("rate limit store failed for %s: %v", check.key, err)
+				continue
+			}
+
+			if !result.Allowed {
+				retryAfter := retryAfterSeconds(result.RetryAfter)
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+				return NewProblem(fiber.StatusTooManyRequests, CodeRateLimited, fmt.Sprintf("Too many requests! Try again in %d seconds.", retryAfter))
+			}
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultLeaderboardSize = 10
+	maxLeaderboardSize     = 100
+)
+
+var (
+	completionPercentiles = []float64{50, 75, 90, 95, 99}
+	completionHistogram   = []time.Duration{
+		time.Hour,
+		6 * time.Hour,
+		24 * time.Hour,
+		3 * 24 * time.Hour,
+		7 * 24 * time.Hour,
+	}
+)
+
+type StatsResponse struct {
+	Registrations              int64                 `json:"registrations"`
+	Submissions                int64                 `json:"submissions"`
+	SubmittedApplicants        int64                 `json:"submitted_applicants"`
+	CorrectApplicants          int64                 `json:"correct_applicants"`
+	PassRate                   float64               `json:"pass_rate"`
+	AttemptsPerApplicant       []AttemptsBucket      `json:"attempts_per_applicant"`
+	TimeToCompletion           TimeToCompletionStats `json:"time_to_completion"`
+	RegistrationToFirstCorrect TimeToCompletionStats `json:"registration_to_first_correct"`
+	FirstFetchToFirstCorrect   TimeToCompletionStats `json:"first_fetch_to_first_correct"`
+	Leaderboard                []LeaderboardEntry    `json:"leaderboard"`
+}
+
+type AttemptsBucket struct {
+	Attempts   int   `json:"attempts"`
+	Applicants int64 `json:"applicants"`
+}
+
+type TimeToCompletionStats struct {
+	Percentiles map[string]TimeToCompletion `json:"percentiles"`
+	Histogram   []CompletionHistogramBucket `json:"histogram"`
+}
+
+type CompletionHistogramBucket struct {
+	UpperBound *TimeToCompletion `json:"upper_bound,omitempty"`
+	Count      int               `json:"count"`
+}
+
+type LeaderboardEntry struct {
+	Rank             int                  `json:"rank"`
+	NUID             domain.NUID          `json:"nuid"`
+	ApplicantName    domain.ApplicantName `json:"name"`
+	TimeToCompletion TimeToCompletion     `json:"time_to_completion"`
+}
+
+func (a *AdminHandler) Stats(c *fiber.Ctx) error {
+	leaderboardSize := c.QueryInt("leaderboard_size", defaultLeaderboardSize)
+
+	if leaderboardSize < 0 || leaderboardSize > maxLeaderboardSize {
+		return NewProblem(fiber.StatusBadRequest, CodeQueryInvalid, fmt.Sprintf("invalid leaderboard_size %s", c.Query("leaderboard_size")))
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	counts, err := a.Storage.Counts(cohort)
+
+	if err != nil {
+		return err
+	}
+
+	distribution, err := a.Storage.AttemptDistribution(cohort)
+
+	if err != nil {
+		return err
+	}
+
+	correctApplicants, err := a.Storage.CorrectApplicants(cohort)
+
+	if err != nil {
+		return err
+	}
+
+	correctActivity, err := a.Storage.CorrectActivity(cohort)
+
+	if err != nil {
+		return err
+	}
+
+	var passRate float64
+	if counts.SubmittedApplicants > 0 {
+		passRate = float64(counts.CorrectApplicants) / float64(counts.SubmittedApplicants)
+	}
+
+	attemptsPerApplicant := make([]AttemptsBucket, len(distribution))
+	for i, bucket := range distribution {
+		attemptsPerApplicant[i] = AttemptsBucket{Attempts: bucket.Attempts, Applicants: bucket.Applicants}
+	}
+
+	sort.Slice(correctApplicants, func(i, j int) bool {
+		return correctCompletionTime(correctApplicants[i]) < correctCompletionTime(correctApplicants[j])
+	})
+
+	durations := make([]time.Duration, len(correctApplicants))
+	for i, applicant := range correctApplicants {
+		durations[i] = correctCompletionTime(applicant)
+	}
+
+	var registrationToFirstCorrect, fetchToFirstCorrect []time.Duration
+	for _, activity := range correctActivity {
+		if duration, ok := registrationToFirstCorrectDuration(activity); ok {
+			registrationToFirstCorrect = append(registrationToFirstCorrect, duration)
+		}
+
+		if duration, ok := firstFetchToFirstCorrectDuration(activity); ok {
+			fetchToFirstCorrect = append(fetchToFirstCorrect, duration)
+		}
+	}
+
+	if leaderboardSize > len(correctApplicants) {
+		leaderboardSize = len(correctApplicants)
+	}
+
+	leaderboard := make([]LeaderboardEntry, leaderboardSize)
+	for i, applicant := range correctApplicants[:leaderboardSize] {
+		nuid, err := domain.ParseNUID(applicant.NUID.String)
+
+		if err != nil {
+			return invalidDatabaseState(c, err)
+		}
+
+		applicantName, err := domain.ParseApplicantName(applicant.ApplicantName.String)
+
+		if err != nil {
+			return invalidDatabaseState(c, err)
+		}
+
+		leaderboard[i] = LeaderboardEntry{
+			Rank:             i + 1,
+			NUID:             *nuid,
+			ApplicantName:    *applicantName,
+			TimeToCompletion: convert(durations[i]),
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(StatsResponse{
+		Registrations:              counts.Registrations,
+		Submissions:                counts.Submissions,
+		SubmittedApplicants:        counts.SubmittedApplicants,
+		CorrectApplicants:          counts.CorrectApplicants,
+		PassRate:                   passRate,
+		AttemptsPerApplicant:       attemptsPerApplicant,
+		TimeToCompletion:           completionStats(durations),
+		RegistrationToFirstCorrect: completionStats(registrationToFirstCorrect),
+		FirstFetchToFirstCorrect:   completionStats(fetchToFirstCorrect),
+		Leaderboard:                leaderboard,
+	})
+}
+
+func sortedDurations(durations []time.Duration) []time.Duration {
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted
+}
+
+func completionStats(durations []time.Duration) TimeToCompletionStats {
+	sorted := sortedDurations(durations)
+
+	percentiles := make(map[string]TimeToCompletion, len(completionPercentiles))
+	if len(sorted) > 0 {
+		for _, p := range completionPercentiles {
+			percentiles[fmt.Sprintf("p%g", p)] = convert(domain.Percentile(sorted, p))
+		}
+	}
+
+	buckets := domain.Histogram(sorted, completionHistogram)
+	histogram := make([]CompletionHistogramBucket, len(buckets))
+	for i, bucket := range buckets {
+		histogram[i] = CompletionHistogramBucket{Count: bucket.Count}
+
+		if bucket.UpperBound != math.MaxInt64 {
+			upperBound := convert(bucket.UpperBound)
+			histogram[i].UpperBound = &upperBound
+		}
+	}
+
+	return TimeToCompletionStats{
+		Percentiles: percentiles,
+		Histogram:   histogram,
+	}
+}
+
+func timeToCompletion(applicant storage.ApplicantDB) time.Duration {
+	return applicant.SubmissionTime.Time.Sub(applicant.RegistrationTime.Time)
+}
+
+func timeToFirstCorrect(applicant storage.ApplicantDB) (time.Duration, bool) {
+	if !applicant.FirstCorrectTime.Valid {
+		return 0, false
+	}
+
+	return applicant.FirstCorrectTime.Time.Sub(applicant.RegistrationTime.Time), true
+}
+
+func correctCompletionTime(applicant storage.ApplicantDB) time.Duration {
+	if duration, ok := timeToFirstCorrect(applicant); ok {
+		return duration
+	}
+
+	return timeToCompletion(applicant)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RotateTokenResponse struct {
+	Token uuid.UUID `json:"token"`
+}
+
+type TokenHistoryEntry struct {
+	Action    storage.TokenAction `json:"action"`
+	Actor     string              `json:"actor"`
+	ChangedAt time.Time           `json:"changed_at"`
+}
+
+func parseNUIDParam(c *fiber.Ctx) (*domain.NUID, error) {
+	rawNUID := c.Params("nuid")
+
+	nuid, err := domain.ParseNUID(rawNUID)
+
+	if err != nil {
+		return nil, NewProblem(fiber.StatusBadRequest, CodeNUIDInvalid, fmt.Sprintf("invalid NUID %s", rawNUID))
+	}
+
+	return nuid, nil
+}
+
+func applicantNotFound(nuid *domain.NUID, err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return NewProblem(fiber.StatusNotFound, CodeApplicantNotFound, fmt.Sprintf("Applicant with NUID %s not found!", nuid))
+	}
+
+	return err
+}
+
+func (a *AdminHandler) RotateToken(c *fiber.Ctx) error {
+	nuid, err := parseNUIDParam(c)
+
+	if err != nil {
+		return err
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	token, err := a.Storage.RotateToken(cohort, *nuid, adminSubject(c))
+
+	if err != nil {
+		return applicantNotFound(nuid, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(RotateTokenResponse{Token: token})
+}
+
+func (a *AdminHandler) RevokeToken(c *fiber.Ctx) error {
+	nuid, err := parseNUIDParam(c)
+
+	if err != nil {
+		return err
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	if err := a.Storage.RevokeToken(cohort, *nuid, adminSubject(c)); err != nil {
+		return applicantNotFound(nuid, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (a *AdminHandler) TokenHistory(c *fiber.Ctx) error {
+	nuid, err := parseNUIDParam(c)
+
+	if err != nil {
+		return err
+	}
+
+	cohort, err := requestCohort(c, a.Cohort)
+
+	if err != nil {
+		return err
+	}
+
+	history, err := a.Storage.TokenHistory(cohort, *nuid)
+
+	if err != nil {
+		return applicantNotFound(nuid, err)
+	}
+
+	entries := make([]TokenHistoryEntry, len(history))
+
+	for i, entry := range history {
+		entries[i] = TokenHistoryEntry{
+			Action:    entry.Action,
+			Actor:     entry.Actor,
+			ChangedAt: entry.ChangedAt,
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"fmt"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+)
+
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+type Mailer interface {
+	Send(message Message) error
+}
+
+func NewMailer(settings config.Settings) (Mailer, error) {
+	switch settings.Mail.Driver {
+	case "", config.MailDriverOutbox:
+		return NewOutboxMailer(settings.Mail.OutboxPath), nil
+	case config.MailDriverSMTP:
+		if settings.Mail.Host == "" || settings.Mail.From == "" {
+			return nil, fmt.Errorf("the %s mail driver requires a host and a from address", config.MailDriverSMTP)
+		}
+
+		return NewSMTPMailer(settings.Mail), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", settings.Mail.Driver)
+	}
+}
@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+type OutboxMailer struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func NewOutboxMailer(path string) *OutboxMailer {
+	return &OutboxMailer{Path: path}
+}
+
+func (o *OutboxMailer) Send(message Message) error {
+	if o.Path == "" {
+		log.Infof("outbox: to=%s subject=%q\n%s", message.To, message.Subject, message.Body)
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	file, err := os.OpenFile(o.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+
+	if err != nil {
+		return err
+	}
+
+	if err := json.NewEncoder(file).Encode(message); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func ReadOutbox(path string) ([]Message, error) {
+	file, err := os.Open(path)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var messages []Message
+
+	decoder := json.NewDecoder(file)
+
+	for decoder.More() {
+		var message Message
+
+		if err := decoder.Decode(&message); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+)
+
+type SMTPMailer struct {
+	Settings config.MailSettings
+}
+
+func NewSMTPMailer(settings config.MailSettings) *SMTPMailer {
+	return &SMTPMailer{Settings: settings}
+}
+
+func (s *SMTPMailer) Send(message Message) error {
+	var auth smtp.Auth
+	if s.Settings.Username != "" {
+		auth = smtp.PlainAuth("", s.Settings.Username, s.Settings.Password, s.Settings.Host)
+	}
+
+	headers := []string{
+		fmt.Sprintf("From: %s", s.Settings.From),
+		fmt.Sprintf("To: %s", message.To),
+		fmt.Sprintf("Subject: %s", message.Subject),
+		fmt.Sprintf("Date: %s", time.Now().Format(time.RFC1123Z)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + strings.ReplaceAll(message.Body, "\n", "\r\n")
+
+	address := fmt.Sprintf("%s:%d", s.Settings.Host, s.Settings.Port)
+
+	return smtp.SendMail(address, auth, s.Settings.From, []string{message.To}, []byte(body))
+}
@@ -1,25 +1,15 @@
 package main
 
 import (
-	"github.com/garrettladley/generate_coding_challenge_server_go/config"
-	"github.com/garrettladley/generate_coding_challenge_server_go/db"
-	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
-	"github.com/garrettladley/generate_coding_challenge_server_go/server"
-	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"fmt"
+	"os"
 
-	"go.uber.org/fx"
+	"github.com/garrettladley/generate_coding_challenge_server_go/cli"
 )
 
 func main() {
-	fx.New(
-		fx.Provide(
-			config.GetConfiguration,
-			db.CreatePostgresConnection,
-			storage.NewAdminStorage,
-			handlers.NewAdminHandler,
-			storage.NewApplicantStorage,
-			handlers.NewApplicantHandler,
-		),
-		fx.Invoke(server.NewFxFiberApp),
-	).Run()
+	if err := cli.Run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
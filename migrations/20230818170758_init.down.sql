@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS submissions;
+
+DROP TABLE IF EXISTS applicants;
+
+DROP DOMAIN IF EXISTS applicant_name_domain;
+
+DROP DOMAIN IF EXISTS nuid_domain;
@@ -0,0 +1,3 @@
+ALTER TABLE applicants
+    DROP COLUMN IF EXISTS challenge_version,
+    DROP COLUMN IF EXISTS challenge_type;
@@ -0,0 +1,3 @@
+ALTER TABLE applicants
+    ADD COLUMN challenge_type text NOT NULL DEFAULT 'color_one_edit_away',
+    ADD COLUMN challenge_version integer NOT NULL DEFAULT 1;
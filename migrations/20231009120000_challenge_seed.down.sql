@@ -0,0 +1,2 @@
+ALTER TABLE applicants
+    DROP COLUMN IF EXISTS seed;
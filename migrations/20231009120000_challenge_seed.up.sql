@@ -0,0 +1,2 @@
+ALTER TABLE applicants
+    ADD COLUMN seed bigint;
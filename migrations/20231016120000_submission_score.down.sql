@@ -0,0 +1,3 @@
+ALTER TABLE submissions
+    DROP COLUMN IF EXISTS percentage,
+    DROP COLUMN IF EXISTS score;
@@ -0,0 +1,3 @@
+ALTER TABLE submissions
+    ADD COLUMN score integer,
+    ADD COLUMN percentage double precision;
@@ -0,0 +1,2 @@
+ALTER TABLE submissions
+    ADD COLUMN submission text[];
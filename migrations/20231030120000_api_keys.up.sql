@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    api_key_id serial PRIMARY KEY,
+    name text NOT NULL,
+    key_hash text UNIQUE NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    revoked_at timestamp with time zone
+);
+
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    audit_id serial PRIMARY KEY,
+    api_key_id integer REFERENCES api_keys (api_key_id),
+    subject text NOT NULL,
+    method text NOT NULL,
+    path text NOT NULL,
+    status integer NOT NULL,
+    request_time timestamp with time zone NOT NULL
+);
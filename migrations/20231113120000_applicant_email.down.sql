@@ -0,0 +1,4 @@
+ALTER TABLE applicants
+    DROP COLUMN IF EXISTS email;
+
+DROP DOMAIN IF EXISTS email_domain;
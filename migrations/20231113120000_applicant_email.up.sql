@@ -0,0 +1,5 @@
+CREATE DOMAIN email_domain AS varchar(254)
+    CHECK (value ~ '^[^@\s]+@[^@\s]+$');
+
+ALTER TABLE applicants
+    ADD COLUMN email email_domain;
@@ -0,0 +1,13 @@
+-- Hashed tokens cannot be turned back into the tokens applicants hold, so refuse to migrate down instead of issuing new ones.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM applicants WHERE length(token_hash) <> 36) THEN
+        RAISE EXCEPTION 'applicants have hashed tokens that cannot be restored; migrating down would lock those applicants out';
+    END IF;
+END $$;
+
+ALTER TABLE applicants
+    ALTER COLUMN token_hash TYPE uuid USING token_hash::uuid;
+
+ALTER TABLE applicants
+    RENAME COLUMN token_hash TO token;
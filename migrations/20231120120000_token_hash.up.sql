@@ -0,0 +1,5 @@
+ALTER TABLE applicants
+    RENAME COLUMN token TO token_hash;
+
+ALTER TABLE applicants
+    ALTER COLUMN token_hash TYPE text USING token_hash::text;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS token_history;
+
+ALTER TABLE applicants
+    DROP COLUMN IF EXISTS token_revoked_at;
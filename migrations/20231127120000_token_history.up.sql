@@ -0,0 +1,16 @@
+ALTER TABLE applicants
+    ADD COLUMN token_revoked_at timestamp with time zone;
+
+CREATE TABLE IF NOT EXISTS token_history (
+    history_id serial PRIMARY KEY,
+    nuid nuid_domain NOT NULL REFERENCES applicants (nuid),
+    action text NOT NULL
+        CHECK (action IN ('issued', 'recovered', 'rotated', 'revoked')),
+    actor text NOT NULL,
+    changed_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS token_history_nuid_idx ON token_history (nuid);
+
+INSERT INTO token_history (nuid, action, actor, changed_at)
+SELECT nuid, 'issued', 'applicant', registration_time FROM applicants;
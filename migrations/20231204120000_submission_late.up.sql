@@ -0,0 +1,2 @@
+ALTER TABLE submissions
+    ADD COLUMN late boolean NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS challenge_fetches (
+    fetch_id serial PRIMARY KEY,
+    nuid nuid_domain NOT NULL REFERENCES applicants (nuid),
+    fetched_at timestamp with time zone NOT NULL,
+    ip text,
+    user_agent text
+);
+
+CREATE INDEX IF NOT EXISTS challenge_fetches_nuid_idx ON challenge_fetches (nuid);
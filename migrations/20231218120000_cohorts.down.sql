@@ -0,0 +1,31 @@
+ALTER TABLE challenge_fetches
+    DROP COLUMN IF EXISTS cohort;
+
+ALTER TABLE token_history
+    DROP COLUMN IF EXISTS cohort;
+
+ALTER TABLE submissions
+    DROP COLUMN IF EXISTS cohort;
+
+ALTER TABLE applicants
+    DROP CONSTRAINT IF EXISTS applicants_pkey,
+    DROP COLUMN IF EXISTS cohort;
+
+ALTER TABLE applicants
+    ADD PRIMARY KEY (nuid);
+
+ALTER TABLE submissions
+    ADD FOREIGN KEY (nuid) REFERENCES applicants (nuid);
+
+ALTER TABLE token_history
+    ADD FOREIGN KEY (nuid) REFERENCES applicants (nuid);
+
+ALTER TABLE challenge_fetches
+    ADD FOREIGN KEY (nuid) REFERENCES applicants (nuid);
+
+CREATE INDEX IF NOT EXISTS token_history_nuid_idx ON token_history (nuid);
+CREATE INDEX IF NOT EXISTS challenge_fetches_nuid_idx ON challenge_fetches (nuid);
+
+DROP TABLE IF EXISTS cohorts;
+
+DROP DOMAIN IF EXISTS cohort_name_domain;
@@ -0,0 +1,62 @@
+CREATE DOMAIN cohort_name_domain AS varchar(64)
+    CHECK (value ~ '^[a-z0-9][a-z0-9_-]*$');
+
+CREATE TABLE IF NOT EXISTS cohorts (
+    name cohort_name_domain PRIMARY KEY,
+    opens_at timestamp with time zone NOT NULL,
+    closes_at timestamp with time zone,
+    closed_at timestamp with time zone,
+    challenge_type text NOT NULL,
+    challenge_version integer NOT NULL,
+    settings jsonb NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone NOT NULL
+);
+
+INSERT INTO cohorts (name, opens_at, challenge_type, challenge_version, created_at)
+SELECT 'default', COALESCE(MIN(registration_time), now()), 'color_one_edit_away', 1, now()
+FROM applicants;
+
+ALTER TABLE submissions
+    DROP CONSTRAINT IF EXISTS submissions_nuid_fkey;
+
+ALTER TABLE token_history
+    DROP CONSTRAINT IF EXISTS token_history_nuid_fkey;
+
+ALTER TABLE challenge_fetches
+    DROP CONSTRAINT IF EXISTS challenge_fetches_nuid_fkey;
+
+ALTER TABLE applicants
+    ADD COLUMN cohort cohort_name_domain NOT NULL DEFAULT 'default' REFERENCES cohorts (name);
+
+ALTER TABLE applicants
+    ALTER COLUMN cohort DROP DEFAULT,
+    DROP CONSTRAINT applicants_pkey,
+    ADD PRIMARY KEY (cohort, nuid);
+
+ALTER TABLE submissions
+    ADD COLUMN cohort cohort_name_domain NOT NULL DEFAULT 'default';
+
+ALTER TABLE submissions
+    ALTER COLUMN cohort DROP DEFAULT,
+    ADD FOREIGN KEY (cohort, nuid) REFERENCES applicants (cohort, nuid);
+
+ALTER TABLE token_history
+    ADD COLUMN cohort cohort_name_domain NOT NULL DEFAULT 'default';
+
+ALTER TABLE token_history
+    ALTER COLUMN cohort DROP DEFAULT,
+    ADD FOREIGN KEY (cohort, nuid) REFERENCES applicants (cohort, nuid);
+
+ALTER TABLE challenge_fetches
+    ADD COLUMN cohort cohort_name_domain NOT NULL DEFAULT 'default';
+
+ALTER TABLE challenge_fetches
+    ALTER COLUMN cohort DROP DEFAULT,
+    ADD FOREIGN KEY (cohort, nuid) REFERENCES applicants (cohort, nuid);
+
+DROP INDEX IF EXISTS token_history_nuid_idx;
+DROP INDEX IF EXISTS challenge_fetches_nuid_idx;
+
+CREATE INDEX IF NOT EXISTS submissions_cohort_nuid_idx ON submissions (cohort, nuid);
+CREATE INDEX IF NOT EXISTS token_history_cohort_nuid_idx ON token_history (cohort, nuid);
+CREATE INDEX IF NOT EXISTS challenge_fetches_cohort_nuid_idx ON challenge_fetches (cohort, nuid);
@@ -0,0 +1,9 @@
+DELETE FROM rate_limit_buckets WHERE bucket_key LIKE 'submit:key:%';
+
+ALTER TABLE rate_limit_buckets ADD COLUMN full_at timestamp with time zone;
+
+UPDATE rate_limit_buckets SET full_at = updated_at;
+
+ALTER TABLE rate_limit_buckets ALTER COLUMN full_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_full_at_idx ON rate_limit_buckets (full_at);
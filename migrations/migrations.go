@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var Postgres embed.FS
+
+//go:embed sqlite/*.sql
+var SQLite embed.FS
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS applicants (
+    nuid varchar(9) PRIMARY KEY
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    applicant_name varchar(256) NOT NULL
+        CHECK (length(applicant_name) <= 256 AND applicant_name NOT GLOB '*[/()"<>\{}]*'),
+    registration_time timestamp NOT NULL,
+    token text UNIQUE NOT NULL,
+    challenge text NOT NULL
+        CHECK (json_type(challenge) = 'array'),
+    solution text NOT NULL
+        CHECK (json_type(solution) = 'array')
+);
+
+CREATE TABLE IF NOT EXISTS submissions (
+    submission_id integer PRIMARY KEY AUTOINCREMENT,
+    nuid varchar(9) NOT NULL REFERENCES applicants (nuid)
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    correct boolean NOT NULL,
+    submission_time timestamp NOT NULL
+);
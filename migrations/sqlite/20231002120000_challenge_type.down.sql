@@ -0,0 +1,5 @@
+ALTER TABLE applicants
+    DROP COLUMN challenge_version;
+
+ALTER TABLE applicants
+    DROP COLUMN challenge_type;
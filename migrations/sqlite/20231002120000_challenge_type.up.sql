@@ -0,0 +1,5 @@
+ALTER TABLE applicants
+    ADD COLUMN challenge_type text NOT NULL DEFAULT 'color_one_edit_away';
+
+ALTER TABLE applicants
+    ADD COLUMN challenge_version integer NOT NULL DEFAULT 1;
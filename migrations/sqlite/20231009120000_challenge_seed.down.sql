@@ -0,0 +1,2 @@
+ALTER TABLE applicants
+    DROP COLUMN seed;
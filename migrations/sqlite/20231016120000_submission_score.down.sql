@@ -0,0 +1,5 @@
+ALTER TABLE submissions
+    DROP COLUMN percentage;
+
+ALTER TABLE submissions
+    DROP COLUMN score;
@@ -0,0 +1,5 @@
+ALTER TABLE submissions
+    ADD COLUMN score integer;
+
+ALTER TABLE submissions
+    ADD COLUMN percentage double precision;
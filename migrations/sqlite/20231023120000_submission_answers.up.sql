@@ -0,0 +1,3 @@
+ALTER TABLE submissions
+    ADD COLUMN submission text
+        CHECK (submission IS NULL OR json_type(submission) = 'array');
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    api_key_id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL,
+    key_hash text UNIQUE NOT NULL,
+    created_at timestamp NOT NULL,
+    revoked_at timestamp
+);
+
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    audit_id integer PRIMARY KEY AUTOINCREMENT,
+    api_key_id integer REFERENCES api_keys (api_key_id),
+    subject text NOT NULL,
+    method text NOT NULL,
+    path text NOT NULL,
+    status integer NOT NULL,
+    request_time timestamp NOT NULL
+);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    bucket_key text PRIMARY KEY,
+    tokens real NOT NULL,
+    updated_at timestamp NOT NULL
+);
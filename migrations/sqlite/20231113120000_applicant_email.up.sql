@@ -0,0 +1,3 @@
+ALTER TABLE applicants
+    ADD COLUMN email varchar(254)
+        CHECK (email IS NULL OR (length(email) <= 254 AND email GLOB '?*@?*' AND email NOT GLOB '*@*@*'));
@@ -0,0 +1,11 @@
+-- Hashed tokens cannot be turned back into the tokens applicants hold, so refuse to migrate down instead of issuing new ones.
+CREATE TEMP TABLE token_hash_down_guard (
+    applicants_with_hashed_tokens integer CHECK (applicants_with_hashed_tokens = 0)
+);
+
+INSERT INTO token_hash_down_guard SELECT COUNT(*) FROM applicants WHERE length(token_hash) <> 36;
+
+DROP TABLE token_hash_down_guard;
+
+ALTER TABLE applicants
+    RENAME COLUMN token_hash TO token;
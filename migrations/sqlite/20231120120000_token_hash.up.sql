@@ -0,0 +1,2 @@
+ALTER TABLE applicants
+    RENAME COLUMN token TO token_hash;
@@ -0,0 +1,17 @@
+ALTER TABLE applicants
+    ADD COLUMN token_revoked_at timestamp;
+
+CREATE TABLE IF NOT EXISTS token_history (
+    history_id integer PRIMARY KEY AUTOINCREMENT,
+    nuid varchar(9) NOT NULL REFERENCES applicants (nuid)
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    action text NOT NULL
+        CHECK (action IN ('issued', 'recovered', 'rotated', 'revoked')),
+    actor text NOT NULL,
+    changed_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS token_history_nuid_idx ON token_history (nuid);
+
+INSERT INTO token_history (nuid, action, actor, changed_at)
+SELECT nuid, 'issued', 'applicant', registration_time FROM applicants;
@@ -0,0 +1,2 @@
+ALTER TABLE submissions
+    DROP COLUMN late;
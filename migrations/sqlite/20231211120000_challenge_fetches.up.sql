@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS challenge_fetches (
+    fetch_id integer PRIMARY KEY AUTOINCREMENT,
+    nuid varchar(9) NOT NULL REFERENCES applicants (nuid)
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    fetched_at timestamp NOT NULL,
+    ip text,
+    user_agent text
+);
+
+CREATE INDEX IF NOT EXISTS challenge_fetches_nuid_idx ON challenge_fetches (nuid);
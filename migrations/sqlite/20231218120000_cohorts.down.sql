@@ -0,0 +1,81 @@
+CREATE TABLE applicants_old (
+    nuid varchar(9) PRIMARY KEY
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    applicant_name varchar(256) NOT NULL
+        CHECK (length(applicant_name) <= 256 AND applicant_name NOT GLOB '*[/()"<>\{}]*'),
+    registration_time timestamp NOT NULL,
+    token_hash text UNIQUE NOT NULL,
+    challenge text NOT NULL
+        CHECK (json_type(challenge) = 'array'),
+    solution text NOT NULL
+        CHECK (json_type(solution) = 'array'),
+    challenge_type text NOT NULL DEFAULT 'color_one_edit_away',
+    challenge_version integer NOT NULL DEFAULT 1,
+    seed bigint,
+    email varchar(254)
+        CHECK (email IS NULL OR (length(email) <= 254 AND email GLOB '?*@?*' AND email NOT GLOB '*@*@*')),
+    token_revoked_at timestamp
+);
+
+INSERT INTO applicants_old (nuid, applicant_name, registration_time, token_hash, challenge, solution, challenge_type, challenge_version, seed, email, token_revoked_at)
+SELECT nuid, applicant_name, registration_time, token_hash, challenge, solution, challenge_type, challenge_version, seed, email, token_revoked_at
+FROM applicants;
+
+CREATE TABLE submissions_old (
+    submission_id integer PRIMARY KEY AUTOINCREMENT,
+    nuid varchar(9) NOT NULL REFERENCES applicants_old (nuid)
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    correct boolean NOT NULL,
+    submission_time timestamp NOT NULL,
+    score integer,
+    percentage double precision,
+    submission text
+        CHECK (submission IS NULL OR json_type(submission) = 'array'),
+    late boolean NOT NULL DEFAULT FALSE
+);
+
+INSERT INTO submissions_old (submission_id, nuid, correct, submission_time, score, percentage, submission, late)
+SELECT submission_id, nuid, correct, submission_time, score, percentage, submission, late
+FROM submissions;
+
+CREATE TABLE token_history_old (
+    history_id integer PRIMARY KEY AUTOINCREMENT,
+    nuid varchar(9) NOT NULL REFERENCES applicants_old (nuid)
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    action text NOT NULL
+        CHECK (action IN ('issued', 'recovered', 'rotated', 'revoked')),
+    actor text NOT NULL,
+    changed_at timestamp NOT NULL
+);
+
+INSERT INTO token_history_old (history_id, nuid, action, actor, changed_at)
+SELECT history_id, nuid, action, actor, changed_at
+FROM token_history;
+
+CREATE TABLE challenge_fetches_old (
+    fetch_id integer PRIMARY KEY AUTOINCREMENT,
+    nuid varchar(9) NOT NULL REFERENCES applicants_old (nuid)
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    fetched_at timestamp NOT NULL,
+    ip text,
+    user_agent text
+);
+
+INSERT INTO challenge_fetches_old (fetch_id, nuid, fetched_at, ip, user_agent)
+SELECT fetch_id, nuid, fetched_at, ip, user_agent
+FROM challenge_fetches;
+
+DROP TABLE challenge_fetches;
+DROP TABLE token_history;
+DROP TABLE submissions;
+DROP TABLE applicants;
+
+ALTER TABLE applicants_old RENAME TO applicants;
+ALTER TABLE submissions_old RENAME TO submissions;
+ALTER TABLE token_history_old RENAME TO token_history;
+ALTER TABLE challenge_fetches_old RENAME TO challenge_fetches;
+
+CREATE INDEX IF NOT EXISTS token_history_nuid_idx ON token_history (nuid);
+CREATE INDEX IF NOT EXISTS challenge_fetches_nuid_idx ON challenge_fetches (nuid);
+
+DROP TABLE IF EXISTS cohorts;
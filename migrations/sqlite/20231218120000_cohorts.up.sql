@@ -0,0 +1,105 @@
+CREATE TABLE IF NOT EXISTS cohorts (
+    name varchar(64) PRIMARY KEY
+        CHECK (length(name) <= 64 AND name GLOB '[a-z0-9]*' AND name NOT GLOB '*[^a-z0-9_-]*'),
+    opens_at timestamp NOT NULL,
+    closes_at timestamp,
+    closed_at timestamp,
+    challenge_type text NOT NULL,
+    challenge_version integer NOT NULL,
+    settings text NOT NULL DEFAULT '{}'
+        CHECK (json_type(settings) = 'object'),
+    created_at timestamp NOT NULL
+);
+
+INSERT INTO cohorts (name, opens_at, challenge_type, challenge_version, created_at)
+SELECT 'default', COALESCE(MIN(registration_time), strftime('%Y-%m-%d %H:%M:%f', 'now')), 'color_one_edit_away', 1, strftime('%Y-%m-%d %H:%M:%f', 'now')
+FROM applicants;
+
+CREATE TABLE applicants_new (
+    cohort varchar(64) NOT NULL REFERENCES cohorts (name),
+    nuid varchar(9) NOT NULL
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    applicant_name varchar(256) NOT NULL
+        CHECK (length(applicant_name) <= 256 AND applicant_name NOT GLOB '*[/()"<>\{}]*'),
+    registration_time timestamp NOT NULL,
+    token_hash text UNIQUE NOT NULL,
+    challenge text NOT NULL
+        CHECK (json_type(challenge) = 'array'),
+    solution text NOT NULL
+        CHECK (json_type(solution) = 'array'),
+    challenge_type text NOT NULL DEFAULT 'color_one_edit_away',
+    challenge_version integer NOT NULL DEFAULT 1,
+    seed bigint,
+    email varchar(254)
+        CHECK (email IS NULL OR (length(email) <= 254 AND email GLOB '?*@?*' AND email NOT GLOB '*@*@*')),
+    token_revoked_at timestamp,
+    PRIMARY KEY (cohort, nuid)
+);
+
+INSERT INTO applicants_new (cohort, nuid, applicant_name, registration_time, token_hash, challenge, solution, challenge_type, challenge_version, seed, email, token_revoked_at)
+SELECT 'default', nuid, applicant_name, registration_time, token_hash, challenge, solution, challenge_type, challenge_version, seed, email, token_revoked_at
+FROM applicants;
+
+CREATE TABLE submissions_new (
+    submission_id integer PRIMARY KEY AUTOINCREMENT,
+    cohort varchar(64) NOT NULL,
+    nuid varchar(9) NOT NULL
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    correct boolean NOT NULL,
+    submission_time timestamp NOT NULL,
+    score integer,
+    percentage double precision,
+    submission text
+        CHECK (submission IS NULL OR json_type(submission) = 'array'),
+    late boolean NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (cohort, nuid) REFERENCES applicants_new (cohort, nuid)
+);
+
+INSERT INTO submissions_new (submission_id, cohort, nuid, correct, submission_time, score, percentage, submission, late)
+SELECT submission_id, 'default', nuid, correct, submission_time, score, percentage, submission, late
+FROM submissions;
+
+CREATE TABLE token_history_new (
+    history_id integer PRIMARY KEY AUTOINCREMENT,
+    cohort varchar(64) NOT NULL,
+    nuid varchar(9) NOT NULL
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    action text NOT NULL
+        CHECK (action IN ('issued', 'recovered', 'rotated', 'revoked')),
+    actor text NOT NULL,
+    changed_at timestamp NOT NULL,
+    FOREIGN KEY (cohort, nuid) REFERENCES applicants_new (cohort, nuid)
+);
+
+INSERT INTO token_history_new (history_id, cohort, nuid, action, actor, changed_at)
+SELECT history_id, 'default', nuid, action, actor, changed_at
+FROM token_history;
+
+CREATE TABLE challenge_fetches_new (
+    fetch_id integer PRIMARY KEY AUTOINCREMENT,
+    cohort varchar(64) NOT NULL,
+    nuid varchar(9) NOT NULL
+        CHECK (length(nuid) = 9 AND nuid NOT GLOB '*[^0-9]*'),
+    fetched_at timestamp NOT NULL,
+    ip text,
+    user_agent text,
+    FOREIGN KEY (cohort, nuid) REFERENCES applicants_new (cohort, nuid)
+);
+
+INSERT INTO challenge_fetches_new (fetch_id, cohort, nuid, fetched_at, ip, user_agent)
+SELECT fetch_id, 'default', nuid, fetched_at, ip, user_agent
+FROM challenge_fetches;
+
+DROP TABLE challenge_fetches;
+DROP TABLE token_history;
+DROP TABLE submissions;
+DROP TABLE applicants;
+
+ALTER TABLE applicants_new RENAME TO applicants;
+ALTER TABLE submissions_new RENAME TO submissions;
+ALTER TABLE token_history_new RENAME TO token_history;
+ALTER TABLE challenge_fetches_new RENAME TO challenge_fetches;
+
+CREATE INDEX IF NOT EXISTS submissions_cohort_nuid_idx ON submissions (cohort, nuid);
+CREATE INDEX IF NOT EXISTS token_history_cohort_nuid_idx ON token_history (cohort, nuid);
+CREATE INDEX IF NOT EXISTS challenge_fetches_cohort_nuid_idx ON challenge_fetches (cohort, nuid);
@@ -0,0 +1,7 @@
+DELETE FROM rate_limit_buckets WHERE bucket_key LIKE 'submit:key:%';
+
+ALTER TABLE rate_limit_buckets ADD COLUMN full_at timestamp NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+UPDATE rate_limit_buckets SET full_at = updated_at;
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_full_at_idx ON rate_limit_buckets (full_at);
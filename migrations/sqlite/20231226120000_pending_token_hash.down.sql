@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS applicants_pending_token_hash_key;
+
+ALTER TABLE applicants DROP COLUMN pending_token_hash;
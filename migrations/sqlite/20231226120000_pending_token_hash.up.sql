@@ -0,0 +1,3 @@
+ALTER TABLE applicants ADD COLUMN pending_token_hash text;
+
+CREATE UNIQUE INDEX IF NOT EXISTS applicants_pending_token_hash_key ON applicants (pending_token_hash);
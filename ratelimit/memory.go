@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+type MemoryStore struct {
+	Now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{Now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (m *MemoryStore) Take(key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.Now()
+
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit.Capacity(), updated: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = limit.refill(b.tokens, now.Sub(b.updated))
+	b.updated = now
+
+	result := limit.result(b.tokens)
+
+	if result.Allowed {
+		b.tokens--
+	}
+
+	b.full = now.Add(limit.untilFull(b.tokens))
+
+	return result, nil
+}
+
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+
+	m.lastSweep = now
+}
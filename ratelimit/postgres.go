@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresStore struct {
+	Conn *sqlx.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(conn *sqlx.DB) *PostgresStore {
+	return &PostgresStore{Conn: conn}
+}
+
+func (p *PostgresStore) Take(key string, limit Limit) (Result, error) {
+	if err := p.sweep(time.Now()); err != nil {
+		return Result{}, err
+	}
+
+	var tokens float64
+
+	err := p.Conn.Get(&tokens,
+		`INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, updated_at, full_at)
+		VALUES ($1, $2::double precision - 1, now(), now() + make_interval(secs => 1 / $3::double precision))
+		ON CONFLICT (bucket_key) DO UPDATE
+		SET tokens = LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at))::double precision * $3::double precision) - 1,
+			updated_at = now(),
+			full_at = now() + make_interval(secs => ($2::double precision - LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at))::double precision * $3::double precision) + 1) / $3::double precision)
+		WHERE LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM (now() - b.updated_at))::double precision * $3::double precision) >= 1
+		RETURNING tokens;`,
+		key, limit.Capacity(), limit.Rate())
+
+	if err == nil {
+		return Result{Allowed: true, Remaining: int(tokens)}, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return Result{}, err
+	}
+
+	err = p.Conn.Get(&tokens,
+		`SELECT LEAST($2::double precision, tokens + EXTRACT(EPOCH FROM (now() - updated_at))::double precision * $3::double precision)
+		FROM rate_limit_buckets WHERE bucket_key=$1;`,
+		key, limit.Capacity(), limit.Rate())
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	return limit.result(tokens), nil
+}
+
+func (p *PostgresStore) sweep(now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if now.Sub(p.lastSweep) < sweepInterval {
+		return nil
+	}
+
+	if _, err := p.Conn.Exec("DELETE FROM rate_limit_buckets WHERE full_at <= now();"); err != nil {
+		return err
+	}
+
+	p.lastSweep = now
+
+	return nil
+}
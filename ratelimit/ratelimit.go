@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+)
+
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+func NewLimit(settings config.RateLimit) Limit {
+	return Limit{Requests: settings.Requests, Per: settings.Per, Burst: settings.Burst}
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+func (l Limit) Capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+
+	return float64(l.Requests)
+}
+
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+func (l Limit) refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return math.Min(l.Capacity(), tokens+elapsed.Seconds()*l.Rate())
+}
+
+func (l Limit) untilFull(tokens float64) time.Duration {
+	return time.Duration((l.Capacity() - tokens) / l.Rate() * float64(time.Second))
+}
+
+func (l Limit) result(tokens float64) Result {
+	if tokens >= 1 {
+		return Result{Allowed: true, Remaining: int(tokens - 1)}
+	}
+
+	return Result{Allowed: false, RetryAfter: time.Duration((1 - tokens) / l.Rate() * float64(time.Second))}
+}
+
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type Store interface {
+	Take(key string, limit Limit) (Result, error)
+}
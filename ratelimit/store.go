@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/db"
+	"go.uber.org/fx"
+)
+
+func OpenStore(settings config.Settings) (Store, func() error, error) {
+	switch settings.Application.RateLimit.Store {
+	case "", config.RateLimitStoreMemory:
+		return NewMemoryStore(), func() error { return nil }, nil
+	case config.RateLimitStorePostgres:
+		if settings.Database.Driver != "" && settings.Database.Driver != config.DriverPostgres {
+			return nil, nil, fmt.Errorf("rate limit store %s requires the %s database driver", config.RateLimitStorePostgres, config.DriverPostgres)
+		}
+
+		conn, err := db.OpenPostgresConnection(settings)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return NewPostgresStore(conn), conn.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown rate limit store: %s", settings.Application.RateLimit.Store)
+	}
+}
+
+func NewStore(lc fx.Lifecycle, settings config.Settings) (Store, error) {
+	store, closeStore, err := OpenStore(settings)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return closeStore()
+		},
+	})
+
+	return store, nil
+}
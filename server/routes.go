@@ -0,0 +1,72 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Route struct {
+	Method   string
+	Path     string
+	Handlers []fiber.Handler
+}
+
+type APIVersion struct {
+	Name   string
+	Routes []Route
+}
+
+func (v APIVersion) Prefix() string {
+	return "/" + v.Name
+}
+
+func V1(applicantHandlers *handlers.ApplicantHandler, adminHandlers *handlers.AdminHandler, authHandlers *handlers.AuthHandler, rateLimitHandlers *handlers.RateLimitHandler) APIVersion {
+	return APIVersion{
+		Name: "v1",
+		Routes: []Route{
+			{fiber.MethodPost, "/register", []fiber.Handler{rateLimitHandlers.Limit("register", handlers.RegisterKey), applicantHandlers.Register}},
+			{fiber.MethodGet, "/forgot_token/:nuid", []fiber.Handler{rateLimitHandlers.Limit("forgot_token", handlers.ParamKey("nuid")), applicantHandlers.ForgotToken}},
+			{fiber.MethodGet, "/challenge/:token", []fiber.Handler{applicantHandlers.Challenge}},
+			{fiber.MethodPost, "/submit/:token", []fiber.Handler{rateLimitHandlers.Limit("submit", rateLimitHandlers.TokenKey("token")), applicantHandlers.Submit}},
+
+			{fiber.MethodGet, "/applicants", []fiber.Handler{authHandlers.Admin, adminHandlers.Applicants}},
+			{fiber.MethodGet, "/stats", []fiber.Handler{authHandlers.Admin, adminHandlers.Stats}},
+			{fiber.MethodGet, "/export/applicants", []fiber.Handler{authHandlers.Admin, adminHandlers.Export}},
+			{fiber.MethodGet, "/applicant/:nuid", []fiber.Handler{authHandlers.Admin, adminHandlers.Applicant}},
+			{fiber.MethodGet, "/applicant/:nuid/submissions", []fiber.Handler{authHandlers.Admin, adminHandlers.Submissions}},
+			{fiber.MethodGet, "/applicant/:nuid/activity", []fiber.Handler{authHandlers.Admin, adminHandlers.Activity}},
+			{fiber.MethodPost, "/applicant/:nuid/token/rotate", []fiber.Handler{authHandlers.Admin, adminHandlers.RotateToken}},
+			{fiber.MethodPost, "/applicant/:nuid/token/revoke", []fiber.Handler{authHandlers.Admin, adminHandlers.RevokeToken}},
+			{fiber.MethodGet, "/applicant/:nuid/token/history", []fiber.Handler{authHandlers.Admin, adminHandlers.TokenHistory}},
+			{fiber.MethodGet, "/submission/:id", []fiber.Handler{authHandlers.Admin, adminHandlers.Submission}},
+			{fiber.MethodGet, "/cohorts", []fiber.Handler{authHandlers.Admin, adminHandlers.ListCohorts}},
+			{fiber.MethodPost, "/cohorts", []fiber.Handler{authHandlers.Admin, adminHandlers.CreateCohort}},
+			{fiber.MethodGet, "/cohorts/compare", []fiber.Handler{authHandlers.Admin, adminHandlers.CompareCohorts}},
+			{fiber.MethodPost, "/cohorts/:name/close", []fiber.Handler{authHandlers.Admin, adminHandlers.CloseCohort}},
+		},
+	}
+}
+
+func mount(router fiber.Router, routes []Route, middleware ...fiber.Handler) {
+	for _, route := range routes {
+		routeHandlers := append(append([]fiber.Handler{}, middleware...), route.Handlers...)
+		router.Add(route.Method, route.Path, routeHandlers...)
+	}
+}
+
+func deprecated(successor APIVersion, sunset *time.Time) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", "true")
+		c.Set(fiber.HeaderLink, fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor.Prefix(), c.Path()))
+
+		if sunset != nil {
+			c.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+
+		return c.Next()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/docs"
 	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -13,8 +14,10 @@ import (
 	"go.uber.org/fx"
 )
 
-func NewFiberApp(address string, applicantHandlers *handlers.ApplicantHandler, adminHandlers *handlers.AdminHandler) *fiber.App {
-	app := fiber.New()
+func NewFiberApp(address string, settings config.ApplicationSettings, applicantHandlers *handlers.ApplicantHandler, adminHandlers *handlers.AdminHandler, authHandlers *handlers.AuthHandler, rateLimitHandlers *handlers.RateLimitHandler) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handlers.ErrorHandler,
+	})
 
 	app.Use(cors.New())
 	app.Use(requestid.New())
@@ -26,21 +29,30 @@ func NewFiberApp(address string, applicantHandlers *handlers.ApplicantHandler, a
 		return c.SendStatus(200)
 	})
 
-	app.Post("/register", applicantHandlers.Register)
-	app.Get("/forgot_token/:nuid", applicantHandlers.ForgotToken)
-	app.Get("/challenge/:token", applicantHandlers.Challenge)
-	app.Post("/submit/:token", applicantHandlers.Submit)
+	app.Get("/openapi.json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Status(fiber.StatusOK).Send(docs.OpenAPI)
+	})
+
+	app.Get("/docs", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Status(fiber.StatusOK).Send(docs.Page)
+	})
+
+	v1 := V1(applicantHandlers, adminHandlers, authHandlers, rateLimitHandlers)
 
-	app.Get("/applicant/:nuid", adminHandlers.Applicant)
+	for _, version := range []APIVersion{v1} {
+		mount(app.Group(version.Prefix()), version.Routes)
+	}
 
-	go app.Listen(address)
+	mount(app, v1.Routes, deprecated(v1, settings.LegacySunset()))
 
 	return app
 }
 
-func NewFxFiberApp(lc fx.Lifecycle, settings config.Settings, applicantHandlers *handlers.ApplicantHandler, adminHandlers *handlers.AdminHandler) *fiber.App {
+func NewFxFiberApp(lc fx.Lifecycle, settings config.Settings, applicantHandlers *handlers.ApplicantHandler, adminHandlers *handlers.AdminHandler, authHandlers *handlers.AuthHandler, rateLimitHandlers *handlers.RateLimitHandler) *fiber.App {
 	address := fmt.Sprintf(":%d", settings.Application.Port)
-	app := NewFiberApp(address, applicantHandlers, adminHandlers)
+	app := NewFiberApp(address, settings.Application, applicantHandlers, adminHandlers, authHandlers, rateLimitHandlers)
 
 	lc.Append(fx.Hook{
 		OnStart: func(context.Context) error {
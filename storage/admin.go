@@ -2,44 +2,386 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 type AdminStorage struct {
-	Conn *sqlx.DB
+	Conn   *sqlx.DB
+	Tokens domain.TokenHasher
 }
 
-func NewAdminStorage(conn *sqlx.DB) *AdminStorage {
-	return &AdminStorage{Conn: conn}
+func NewAdminStorage(conn *sqlx.DB, tokens domain.TokenHasher) *AdminStorage {
+	return &AdminStorage{Conn: conn, Tokens: tokens}
 }
 
 type ApplicantDB struct {
-	NUID             sql.NullString `db:"nuid"`
-	ApplicantName    sql.NullString `db:"applicant_name"`
-	Correct          sql.NullBool   `db:"correct"`
-	SubmissionTime   sql.NullTime   `db:"submission_time"`
-	RegistrationTime sql.NullTime   `db:"registration_time"`
+	Cohort                sql.NullString  `db:"cohort"`
+	NUID                  sql.NullString  `db:"nuid"`
+	ApplicantName         sql.NullString  `db:"applicant_name"`
+	Correct               sql.NullBool    `db:"correct"`
+	Score                 sql.NullInt64   `db:"score"`
+	Percentage            sql.NullFloat64 `db:"percentage"`
+	SubmissionTime        sql.NullTime    `db:"submission_time"`
+	RegistrationTime      sql.NullTime    `db:"registration_time"`
+	Late                  sql.NullBool    `db:"late"`
+	FirstCorrectTime      sql.NullTime    `db:"first_correct_time"`
+	AttemptsBeforeSuccess sql.NullInt64   `db:"attempts_before_success"`
 }
 
-func (s *AdminStorage) Applicant(nuid domain.NUID) (ApplicantDB, error) {
-	var applicant ApplicantDB
-	err := s.Conn.Get(&applicant, `
-	SELECT a.nuid, a.applicant_name, s.correct, s.submission_time, a.registration_time
+const applicantsWithLatestSubmission = `
+	SELECT a.cohort, a.nuid, a.applicant_name, s.correct, s.score, s.percentage, s.submission_time, a.registration_time, s.late,
+		   c.first_correct_time, c.attempts_before_success
 	FROM applicants a
 	LEFT JOIN (
-		SELECT nuid, correct, submission_time,
-			   ROW_NUMBER() OVER (PARTITION BY nuid ORDER BY submission_time DESC) AS row_num
+		SELECT cohort, nuid, correct, score, percentage, submission_time, late,
+			   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY submission_time DESC) AS row_num
 		FROM submissions
-	) s ON a.nuid = s.nuid AND s.row_num = 1
-	WHERE a.nuid = $1;
-`, nuid)
+	) s ON a.cohort = s.cohort AND a.nuid = s.nuid AND s.row_num = 1
+	LEFT JOIN (
+		SELECT cohort, nuid, submission_time AS first_correct_time, attempt - 1 AS attempts_before_success
+		FROM (
+			SELECT cohort, nuid, correct, submission_time,
+				   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY submission_time, submission_id) AS attempt,
+				   ROW_NUMBER() OVER (PARTITION BY cohort, nuid, correct ORDER BY submission_time, submission_id) AS correct_rank
+			FROM submissions
+		) ranked
+		WHERE correct AND correct_rank = 1
+	) c ON a.cohort = c.cohort AND a.nuid = c.nuid
+`
 
-	if err != nil {
+func (s *AdminStorage) Applicant(cohort domain.CohortName, nuid domain.NUID) (ApplicantDB, error) {
+	var applicant ApplicantDB
+	err := s.Conn.Get(&applicant, applicantsWithLatestSubmission+"WHERE a.cohort = $1 AND a.nuid = $2;", cohort, nuid)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ApplicantDB{}, ErrNotFound
+	} else if err != nil {
 		return ApplicantDB{}, fmt.Errorf("failed to query database: %v", err)
 	}
 
 	return applicant, nil
 }
+
+type ApplicantSort string
+
+const (
+	SortRegistrationTime ApplicantSort = "registration_time"
+	SortTimeToCompletion ApplicantSort = "time_to_completion"
+)
+
+func ParseApplicantSort(str string) (ApplicantSort, error) {
+	switch ApplicantSort(str) {
+	case SortRegistrationTime:
+		return SortRegistrationTime, nil
+	case SortTimeToCompletion:
+		return SortTimeToCompletion, nil
+	default:
+		return "", fmt.Errorf("invalid sort: %s", str)
+	}
+}
+
+func (s ApplicantSort) expression() string {
+	switch s {
+	case SortTimeToCompletion:
+		return "COALESCE(EXTRACT(EPOCH FROM (submission_time - registration_time))::double precision, 'Infinity'::double precision)"
+	default:
+		return "EXTRACT(EPOCH FROM registration_time)::double precision"
+	}
+}
+
+type ApplicantFilter struct {
+	Correct         *bool
+	Submitted       *bool
+	RegisteredAfter *time.Time
+}
+
+type ApplicantCursor struct {
+	SortKey float64
+	NUID    string
+}
+
+type ApplicantsQuery struct {
+	Cohort     domain.CohortName
+	Filter     ApplicantFilter
+	Sort       ApplicantSort
+	Descending bool
+	After      *ApplicantCursor
+	Limit      int
+}
+
+type ApplicantPageDB struct {
+	ApplicantDB
+	SortKey float64 `db:"sort_key"`
+}
+
+func (s *AdminStorage) Applicants(query ApplicantsQuery) ([]ApplicantPageDB, error) {
+	var conditions []string
+	var args []interface{}
+
+	addArg := func(arg interface{}) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions = append(conditions, fmt.Sprintf("cohort = %s", addArg(query.Cohort)))
+
+	if query.Filter.Correct != nil {
+		conditions = append(conditions, fmt.Sprintf("correct = %s", addArg(*query.Filter.Correct)))
+	}
+
+	if query.Filter.Submitted != nil {
+		if *query.Filter.Submitted {
+			conditions = append(conditions, "submission_time IS NOT NULL")
+		} else {
+			conditions = append(conditions, "submission_time IS NULL")
+		}
+	}
+
+	if query.Filter.RegisteredAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("registration_time > %s", addArg(*query.Filter.RegisteredAfter)))
+	}
+
+	comparator, direction := ">", "ASC"
+	if query.Descending {
+		comparator, direction = "<", "DESC"
+	}
+
+	if query.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(sort_key, nuid) %s (%s, %s)", comparator, addArg(query.After.SortKey), addArg(query.After.NUID)))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	statement := fmt.Sprintf(`
+	SELECT * FROM (
+		SELECT l.*, %s AS sort_key
+		FROM (%s) l
+	) k
+	%s
+	ORDER BY sort_key %s, nuid %s
+	LIMIT %s;
+`, query.Sort.expression(), applicantsWithLatestSubmission, where, direction, direction, addArg(query.Limit))
+
+	var applicants []ApplicantPageDB
+	err := s.Conn.Select(&applicants, statement, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return applicants, nil
+}
+
+type SubmissionDB struct {
+	SubmissionID   int64           `db:"submission_id"`
+	Cohort         sql.NullString  `db:"cohort"`
+	NUID           sql.NullString  `db:"nuid"`
+	Correct        sql.NullBool    `db:"correct"`
+	Score          sql.NullInt64   `db:"score"`
+	Percentage     sql.NullFloat64 `db:"percentage"`
+	SubmissionTime sql.NullTime    `db:"submission_time"`
+	Submission     StringArray     `db:"submission"`
+	Solution       StringArray     `db:"solution"`
+	Late           sql.NullBool    `db:"late"`
+}
+
+func (s *AdminStorage) Submission(submissionID int64) (SubmissionDB, error) {
+	var submission SubmissionDB
+	err := s.Conn.Get(&submission, `
+	SELECT s.submission_id, s.cohort, s.nuid, s.correct, s.score, s.percentage, s.submission_time, s.submission, a.solution, s.late
+	FROM submissions s
+	JOIN applicants a ON a.cohort = s.cohort AND a.nuid = s.nuid
+	WHERE s.submission_id = $1;
+`, submissionID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return SubmissionDB{}, ErrNotFound
+	} else if err != nil {
+		return SubmissionDB{}, err
+	}
+
+	return submission, nil
+}
+
+func (s *AdminStorage) Submissions(cohort domain.CohortName, nuid domain.NUID) ([]SubmissionDB, error) {
+	var submissions []SubmissionDB
+	err := s.Conn.Select(&submissions, `
+	SELECT s.submission_id, s.cohort, s.nuid, s.correct, s.score, s.percentage, s.submission_time, s.submission, a.solution, s.late
+	FROM submissions s
+	JOIN applicants a ON a.cohort = s.cohort AND a.nuid = s.nuid
+	WHERE s.cohort = $1 AND s.nuid = $2
+	ORDER BY s.submission_time ASC;
+`, cohort, nuid)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return submissions, nil
+}
+
+func (s *AdminStorage) DeleteApplicant(cohort domain.CohortName, nuid domain.NUID) error {
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM submissions WHERE cohort = $1 AND nuid = $2;", cohort, nuid); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM token_history WHERE cohort = $1 AND nuid = $2;", cohort, nuid); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM challenge_fetches WHERE cohort = $1 AND nuid = $2;", cohort, nuid); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM applicants WHERE cohort = $1 AND nuid = $2;", cohort, nuid)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+func updateApplicant(tx *sqlx.Tx, query string, args ...interface{}) error {
+	result, err := tx.Exec(query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *AdminStorage) RotateToken(cohort domain.CohortName, nuid domain.NUID, actor string) (uuid.UUID, error) {
+	token := uuid.New()
+
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	defer tx.Rollback()
+
+	if err := updateApplicant(tx, "UPDATE applicants SET token_hash = $1, pending_token_hash = NULL, token_revoked_at = NULL WHERE cohort = $2 AND nuid = $3;", s.Tokens.Hash(token.String()), cohort, nuid); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if err := recordTokenChange(tx, cohort.String(), nuid.String(), TokenRotated, actor, time.Now()); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return token, tx.Commit()
+}
+
+func (s *AdminStorage) RevokeToken(cohort domain.CohortName, nuid domain.NUID, actor string) error {
+	revokedAt := time.Now()
+
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := updateApplicant(tx, "UPDATE applicants SET token_revoked_at = $1, pending_token_hash = NULL WHERE cohort = $2 AND nuid = $3;", revokedAt, cohort, nuid); err != nil {
+		return err
+	}
+
+	if err := recordTokenChange(tx, cohort.String(), nuid.String(), TokenRevoked, actor, revokedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *AdminStorage) TokenHistory(cohort domain.CohortName, nuid domain.NUID) ([]TokenHistoryDB, error) {
+	history := []TokenHistoryDB{}
+	err := s.Conn.Select(&history, "SELECT history_id, cohort, nuid, action, actor, changed_at FROM token_history WHERE cohort = $1 AND nuid = $2 ORDER BY changed_at ASC, history_id ASC;", cohort, nuid)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	if len(history) == 0 {
+		if _, err := s.Solution(cohort, nuid); err != nil {
+			return nil, err
+		}
+	}
+
+	return history, nil
+}
+
+func (s *AdminStorage) Solution(cohort domain.CohortName, nuid domain.NUID) (SubmitDB, error) {
+	var dbResult SubmitDB
+	err := s.Conn.Get(&dbResult, "SELECT cohort, nuid, solution, challenge_type, challenge_version, registration_time FROM applicants WHERE cohort=$1 AND nuid=$2;", cohort, nuid)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return SubmitDB{}, ErrNotFound
+	} else if err != nil {
+		return SubmitDB{}, err
+	}
+
+	return dbResult, nil
+}
+
+func (s *AdminStorage) HashLegacyTokens() (int, error) {
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	var legacy []legacyTokenDB
+	err = tx.Select(&legacy, "SELECT cohort, nuid, token_hash FROM applicants WHERE length(token_hash) = $1 FOR UPDATE;", legacyTokenLength)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range legacy {
+		if _, err := tx.Exec("UPDATE applicants SET token_hash = $1 WHERE cohort = $2 AND nuid = $3;", s.Tokens.Hash(row.Token), row.Cohort, row.NUID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(legacy), tx.Commit()
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type APIKeyStorage struct {
+	Conn *sqlx.DB
+}
+
+func NewAPIKeyStorage(conn *sqlx.DB) *APIKeyStorage {
+	return &APIKeyStorage{Conn: conn}
+}
+
+type APIKeyDB struct {
+	APIKeyID  int64        `db:"api_key_id"`
+	Name      string       `db:"name"`
+	CreatedAt time.Time    `db:"created_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+}
+
+func (s *APIKeyStorage) Create(name string) (APIKeyDB, string, error) {
+	key, err := domain.GenerateAPIKey()
+
+	if err != nil {
+		return APIKeyDB{}, "", err
+	}
+
+	var apiKey APIKeyDB
+	err = s.Conn.Get(&apiKey, "INSERT INTO api_keys (name, key_hash, created_at) VALUES ($1, $2, $3) RETURNING api_key_id, name, created_at, revoked_at;", name, domain.HashAPIKey(key), time.Now())
+
+	if err != nil {
+		return APIKeyDB{}, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+func (s *APIKeyStorage) Revoke(apiKeyID int64) error {
+	result, err := s.Conn.Exec("UPDATE api_keys SET revoked_at = $1 WHERE api_key_id = $2 AND revoked_at IS NULL;", time.Now(), apiKeyID)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("no active API key with ID %d", apiKeyID)
+	}
+
+	return nil
+}
+
+func (s *APIKeyStorage) List() ([]APIKeyDB, error) {
+	var apiKeys []APIKeyDB
+	err := s.Conn.Select(&apiKeys, "SELECT api_key_id, name, created_at, revoked_at FROM api_keys ORDER BY api_key_id;")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+func (s *APIKeyStorage) Authenticate(key string) (APIKeyDB, error) {
+	var apiKey APIKeyDB
+	err := s.Conn.Get(&apiKey, "SELECT api_key_id, name, created_at, revoked_at FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL;", domain.HashAPIKey(key))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIKeyDB{}, ErrNotFound
+	} else if err != nil {
+		return APIKeyDB{}, err
+	}
+
+	return apiKey, nil
+}
+
+type AdminAuditEntry struct {
+	APIKeyID    *int64
+	Subject     string
+	Method      string
+	Path        string
+	Status      int
+	RequestTime time.Time
+}
+
+func (s *APIKeyStorage) RecordAudit(entry AdminAuditEntry) error {
+	insertStatement := "INSERT INTO admin_audit_log (api_key_id, subject, method, path, status, request_time) VALUES ($1, $2, $3, $4, $5, $6);"
+	_, err := s.Conn.Exec(insertStatement, entry.APIKeyID, entry.Subject, entry.Method, entry.Path, entry.Status, entry.RequestTime)
+
+	return err
+}
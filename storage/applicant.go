@@ -3,7 +3,6 @@ package storage
 import (
 	"database/sql"
 	"errors"
-	"strings"
 	"time"
 
 	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
@@ -13,11 +12,13 @@ import (
 )
 
 type ApplicantStorage struct {
-	Conn *sqlx.DB
+	Conn       *sqlx.DB
+	Challenges *domain.ChallengeRegistry
+	Tokens     domain.TokenHasher
 }
 
-func NewApplicantStorage(conn *sqlx.DB) *ApplicantStorage {
-	return &ApplicantStorage{Conn: conn}
+func NewApplicantStorage(conn *sqlx.DB, challenges *domain.ChallengeRegistry, tokens domain.TokenHasher) *ApplicantStorage {
+	return &ApplicantStorage{Conn: conn, Challenges: challenges, Tokens: tokens}
 }
 
 type RegisterResult struct {
@@ -28,86 +29,134 @@ type RegisterResult struct {
 func (s *ApplicantStorage) Register(applicant domain.Applicant) (RegisterResult, error) {
 	registrationTime := time.Now()
 	token := uuid.New()
-	red, _ := domain.Red.String()
-	orange, _ := domain.Orange.String()
-	yellow, _ := domain.Yellow.String()
-	green, _ := domain.Green.String()
-	blue, _ := domain.Blue.String()
-	violet, _ := domain.Violet.String()
-	challenge := domain.GenerateChallenge(100, []string{"", red, orange, yellow, green, blue, violet})
+	seed := domain.GenerateSeed()
 
-	insertSataement := "INSERT INTO applicants (nuid, applicant_name, registration_time, token, challenge, solution) VALUES ($1, $2, $3, $4, $5, $6);"
-	_, err := s.Conn.Exec(insertSataement, applicant.NUID, applicant.Name, registrationTime, token, pq.Array(challenge.Challenge), pq.Array(challenge.Solution))
+	tx, err := s.Conn.Beginx()
 
 	if err != nil {
 		return RegisterResult{}, err
 	}
 
+	defer tx.Rollback()
+
+	var cohort CohortDB
+	err = tx.Get(&cohort, "SELECT "+cohortColumns+" FROM cohorts WHERE name = $1 FOR SHARE;", applicant.Cohort)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return RegisterResult{}, ErrCohortNotFound
+	} else if err != nil {
+		return RegisterResult{}, err
+	}
+
+	generator, err := registrationCohort(s.Challenges, cohort, registrationTime)
+
+	if err != nil {
+		return RegisterResult{}, err
+	}
+
+	challenge := generator.Generate(seed)
+
+	insertSataement := "INSERT INTO applicants (cohort, nuid, applicant_name, email, registration_time, token_hash, challenge, solution, challenge_type, challenge_version, seed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);"
+	_, err = tx.Exec(insertSataement, applicant.Cohort, applicant.NUID, applicant.Name, applicant.Email, registrationTime, s.Tokens.Hash(token.String()), StringArray(challenge.Challenge), StringArray(challenge.Solution), generator.Name(), generator.Version(), seed)
+
+	if pgErr, isPGError := err.(*pq.Error); isPGError && pgErr.Code == "23505" {
+		return RegisterResult{}, ErrAlreadyRegistered
+	} else if err != nil {
+		return RegisterResult{}, err
+	}
+
+	if err := recordTokenChange(tx, applicant.Cohort.String(), applicant.NUID.String(), TokenIssued, ApplicantActor, registrationTime); err != nil {
+		return RegisterResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return RegisterResult{}, err
+	}
+
 	return RegisterResult{Token: token, Challenge: challenge.Challenge}, nil
 }
 
 type ForgotTokenDB struct {
-	Token sql.NullString `db:"token"`
+	Token         sql.NullString `db:"token"`
+	ApplicantName sql.NullString `db:"applicant_name"`
+	Email         sql.NullString `db:"email"`
 }
 
-func (s *ApplicantStorage) ForgotToken(nuid domain.NUID) (ForgotTokenDB, error) {
+type pendingTokenDB struct {
+	Cohort string `db:"cohort"`
+	NUID   string `db:"nuid"`
+}
+
+func (s *ApplicantStorage) ForgotToken(cohort domain.CohortName, nuid domain.NUID) (ForgotTokenDB, error) {
+	token := uuid.New()
+
 	var dbResult ForgotTokenDB
-	err := s.Conn.Get(&dbResult, "SELECT token FROM applicants WHERE nuid=$1;", nuid)
+	err := s.Conn.Get(&dbResult, "UPDATE applicants SET pending_token_hash=$1 WHERE cohort=$2 AND nuid=$3 AND email IS NOT NULL AND token_revoked_at IS NULL RETURNING applicant_name, email;", s.Tokens.Hash(token.String()), cohort, nuid)
 
-	if err != nil {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ForgotTokenDB{}, ErrNotFound
+	} else if err != nil {
 		return ForgotTokenDB{}, err
 	}
 
-	return dbResult, nil
-}
+	dbResult.Token = sql.NullString{String: token.String(), Valid: true}
 
-type ChallengeDB struct {
-	Challenge StringArray `db:"challenge"`
+	return dbResult, nil
 }
 
-type StringArray []string
+func (s *ApplicantStorage) promotePendingToken(tokenHash string) error {
+	tx, err := s.Conn.Beginx()
 
-func (a *StringArray) Scan(src interface{}) error {
-	if src == nil {
-		*a = nil
-		return nil
+	if err != nil {
+		return err
 	}
 
-	strArray := strings.Split(string(src.([]byte)), ",")
-	for i, s := range strArray {
-		s = strings.Trim(s, "\"")
+	defer tx.Rollback()
 
-		if s == "\\\"" {
-			s = ""
-		}
+	var promoted pendingTokenDB
+	err = tx.Get(&promoted, "UPDATE applicants SET token_hash=pending_token_hash, pending_token_hash=NULL WHERE pending_token_hash=$1 AND token_revoked_at IS NULL RETURNING cohort, nuid;", tokenHash)
 
-		strArray[i] = s
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
 	}
 
-	first := strArray[0]
-	if strings.HasPrefix(first, "{") {
-		strArray[0] = strings.TrimPrefix(first, "{")
-	} else {
-		return errors.New("invalid array format: first element does not start with '{'")
+	if err := recordTokenChange(tx, promoted.Cohort, promoted.NUID, TokenRecovered, ApplicantActor, time.Now()); err != nil {
+		return err
 	}
 
-	lastIndex := len(strArray) - 1
-	last := strArray[lastIndex]
-	if strings.HasSuffix(last, "}") {
-		strArray[lastIndex] = strings.TrimSuffix(last, "}")
-	} else {
-		return errors.New("invalid array format: last element does not end with '}'")
-	}
+	return tx.Commit()
+}
 
-	*a = strArray
-	return nil
+type ChallengeDB struct {
+	Cohort         sql.NullString `db:"cohort"`
+	NUID           sql.NullString `db:"nuid"`
+	Challenge      StringArray    `db:"challenge"`
+	TokenRevokedAt sql.NullTime   `db:"token_revoked_at"`
 }
 
-func (s *ApplicantStorage) Challenge(token uuid.UUID) (ChallengeDB, error) {
+func (s *ApplicantStorage) Challenge(token uuid.UUID, fetch ChallengeFetch) (ChallengeDB, error) {
+	tokenHash := s.Tokens.Hash(token.String())
+
+	if err := s.promotePendingToken(tokenHash); err != nil {
+		return ChallengeDB{}, err
+	}
+
 	var dbResult ChallengeDB
-	err := s.Conn.Get(&dbResult, "SELECT challenge FROM applicants WHERE token=$1;", token)
+	err := s.Conn.Get(&dbResult, "SELECT cohort, nuid, challenge, token_revoked_at FROM applicants WHERE token_hash=$1;", tokenHash)
 
-	if err != nil {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ChallengeDB{}, ErrNotFound
+	} else if err != nil {
+		return ChallengeDB{}, err
+	}
+
+	if dbResult.TokenRevokedAt.Valid {
+		return ChallengeDB{}, ErrTokenRevoked
+	}
+
+	if err := s.recordChallengeFetch(dbResult.Cohort.String, dbResult.NUID.String, fetch, time.Now()); err != nil {
 		return ChallengeDB{}, err
 	}
 
@@ -120,41 +169,91 @@ type SubmitResult struct {
 	HttpStatus int    `json:"-"`
 }
 type SubmitDB struct {
-	NUID     sql.NullString `db:"nuid"`
-	Solution StringArray    `db:"solution"`
+	Cohort           sql.NullString `db:"cohort"`
+	NUID             sql.NullString `db:"nuid"`
+	Solution         StringArray    `db:"solution"`
+	ChallengeType    sql.NullString `db:"challenge_type"`
+	ChallengeVersion sql.NullInt64  `db:"challenge_version"`
+	TokenRevokedAt   sql.NullTime   `db:"token_revoked_at"`
+	RegistrationTime sql.NullTime   `db:"registration_time"`
 }
 
 func (s *ApplicantStorage) Submit(token uuid.UUID, givenSolution []string) (SubmitDB, error) {
+	tokenHash := s.Tokens.Hash(token.String())
+
+	if err := s.promotePendingToken(tokenHash); err != nil {
+		return SubmitDB{}, err
+	}
+
 	var dbResult SubmitDB
-	err := s.Conn.Get(&dbResult, "SELECT nuid, solution FROM applicants WHERE token=$1;", token)
+	err := s.Conn.Get(&dbResult, "SELECT cohort, nuid, solution, challenge_type, challenge_version, token_revoked_at, registration_time FROM applicants WHERE token_hash=$1;", tokenHash)
 
-	if err != nil {
+	if errors.Is(err, sql.ErrNoRows) {
+		return SubmitDB{}, ErrNotFound
+	} else if err != nil {
 		return SubmitDB{}, err
 	}
 
+	if dbResult.TokenRevokedAt.Valid {
+		return SubmitDB{}, ErrTokenRevoked
+	}
+
 	return dbResult, nil
 }
 
-func (s *ApplicantStorage) WriteSubmit(nuid domain.NUID, givenSolution []string, actualSolution []string) (bool, error) {
-	var correct bool
-	if len(givenSolution) == len(actualSolution) {
-		correct = true
-		for i := range givenSolution {
-			if givenSolution[i] != actualSolution[i] {
-				correct = false
-				break
-			}
-		}
-	} else {
-		correct = false
+func (s *ApplicantStorage) WriteSubmit(nuid domain.NUID, submission SubmitDB, givenSolution []string, policy SubmissionPolicy) (SubmitOutcome, error) {
+	grade, err := gradeSubmission(s.Challenges, submission, givenSolution)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	defer tx.Rollback()
+
+	var lockedNUID string
+	err = tx.Get(&lockedNUID, "SELECT nuid FROM applicants WHERE cohort=$1 AND nuid=$2 FOR UPDATE;", submission.Cohort, nuid)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return SubmitOutcome{}, ErrNotFound
+	} else if err != nil {
+		return SubmitOutcome{}, err
 	}
 
-	insertStatement := "INSERT INTO submissions (nuid, correct, submission_time) VALUES ($1, $2, $3);"
-	_, err := s.Conn.Exec(insertStatement, nuid, correct, time.Now())
+	var history attemptHistoryDB
+	err = tx.Get(&history, "SELECT COUNT(*) AS attempts, MAX(submission_time) AS last_submission FROM submissions WHERE cohort=$1 AND nuid=$2;", submission.Cohort, nuid)
 
 	if err != nil {
-		return correct, err
+		return SubmitOutcome{}, err
+	}
+
+	submissionTime := time.Now()
+
+	late, err := policy.Deadline.check(submission.RegistrationTime, submissionTime)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	if err := policy.Check(history.Attempts, history.LastSubmission, submissionTime); err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	insertStatement := "INSERT INTO submissions (cohort, nuid, correct, submission_time, score, percentage, submission, late) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);"
+	_, err = tx.Exec(insertStatement, submission.Cohort, nuid, grade.Correct(), submissionTime, grade.Score, grade.Percentage, StringArray(givenSolution), late)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return SubmitOutcome{}, err
 	}
 
-	return correct, err
+	return policy.outcome(grade, history.Attempts+1, late), nil
 }
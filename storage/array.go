@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type StringArray []string
+
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+
+	for i, s := range a {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteByte('"')
+		for j := 0; j < len(s); j++ {
+			if s[j] == '"' || s[j] == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(s[j])
+		}
+		b.WriteByte('"')
+	}
+
+	b.WriteByte('}')
+
+	return b.String(), nil
+}
+
+func (a *StringArray) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return a.parse(string(src))
+	case string:
+		return a.parse(src)
+	default:
+		return fmt.Errorf("invalid array format: unsupported type %T", src)
+	}
+}
+
+func (a *StringArray) parse(src string) error {
+	if !strings.HasPrefix(src, "{") {
+		return errors.New("invalid array format: array does not start with '{'")
+	}
+
+	if !strings.HasSuffix(src, "}") || len(src) < 2 {
+		return errors.New("invalid array format: array does not end with '}'")
+	}
+
+	body := src[1 : len(src)-1]
+	result := []string{}
+
+	if body == "" {
+		*a = result
+		return nil
+	}
+
+	for i := 0; ; {
+		var element strings.Builder
+		quoted, escaped := false, false
+
+		if i < len(body) && body[i] == '"' {
+			quoted = true
+			i++
+
+			for {
+				if i >= len(body) {
+					return errors.New("invalid array format: unterminated quoted element")
+				}
+
+				c := body[i]
+
+				if c == '\\' {
+					if i+1 >= len(body) {
+						return errors.New("invalid array format: dangling escape")
+					}
+
+					element.WriteByte(body[i+1])
+					i += 2
+					continue
+				}
+
+				if c == '"' {
+					i++
+					break
+				}
+
+				element.WriteByte(c)
+				i++
+			}
+		} else {
+			for i < len(body) && body[i] != ',' {
+				c := body[i]
+
+				switch c {
+				case '{', '}', '"':
+					return fmt.Errorf("invalid array format: unexpected %q in unquoted element", c)
+				case '\\':
+					if i+1 >= len(body) {
+						return errors.New("invalid array format: dangling escape")
+					}
+
+					element.WriteByte(body[i+1])
+					escaped = true
+					i += 2
+					continue
+				}
+
+				element.WriteByte(c)
+				i++
+			}
+		}
+
+		if !quoted {
+			trimmed := strings.TrimSpace(element.String())
+
+			if !escaped && strings.EqualFold(trimmed, "NULL") {
+				return errors.New("invalid array format: NULL elements are not supported")
+			}
+
+			element.Reset()
+			element.WriteString(trimmed)
+		}
+
+		result = append(result, element.String())
+
+		if i >= len(body) {
+			break
+		}
+
+		if body[i] != ',' {
+			return fmt.Errorf("invalid array format: expected ',' but found %q", body[i])
+		}
+
+		i++
+	}
+
+	*a = result
+	return nil
+}
@@ -0,0 +1,10 @@
+package storage
+
+import (
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+)
+
+func NewChallengeGenerator(settings config.Settings, challenges *domain.ChallengeRegistry) (domain.ChallengeGenerator, error) {
+	return challenges.Resolve(settings.Challenge.Type, settings.Challenge.Version)
+}
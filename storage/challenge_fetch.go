@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+)
+
+type ChallengeFetch struct {
+	IP        string
+	UserAgent string
+}
+
+type ActivityDB struct {
+	Cohort           sql.NullString `db:"cohort"`
+	NUID             sql.NullString `db:"nuid"`
+	RegistrationTime sql.NullTime   `db:"registration_time"`
+	Fetches          int64          `db:"fetches"`
+	FirstFetch       sql.NullTime   `db:"first_fetch"`
+	LastFetch        sql.NullTime   `db:"last_fetch"`
+	LastIP           sql.NullString `db:"last_ip"`
+	LastUserAgent    sql.NullString `db:"last_user_agent"`
+	FirstCorrect     sql.NullTime   `db:"first_correct"`
+}
+
+func nullString(str string) sql.NullString {
+	return sql.NullString{String: str, Valid: str != ""}
+}
+
+const applicantActivity = `
+	SELECT a.cohort, a.nuid, a.registration_time,
+		   COALESCE(f.fetches, 0) AS fetches, f.first_fetch, f.last_fetch,
+		   l.ip AS last_ip, l.user_agent AS last_user_agent,
+		   c.first_correct
+	FROM applicants a
+	LEFT JOIN (
+		SELECT cohort, nuid, COUNT(*) AS fetches, MIN(fetched_at) AS first_fetch, MAX(fetched_at) AS last_fetch
+		FROM challenge_fetches
+		GROUP BY cohort, nuid
+	) f ON a.cohort = f.cohort AND a.nuid = f.nuid
+	LEFT JOIN (
+		SELECT cohort, nuid, ip, user_agent,
+			   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY fetched_at DESC, fetch_id DESC) AS row_num
+		FROM challenge_fetches
+	) l ON a.cohort = l.cohort AND a.nuid = l.nuid AND l.row_num = 1
+	LEFT JOIN (
+		SELECT cohort, nuid, MIN(submission_time) AS first_correct
+		FROM submissions
+		WHERE correct
+		GROUP BY cohort, nuid
+	) c ON a.cohort = c.cohort AND a.nuid = c.nuid
+`
+
+func (s *AdminStorage) Activity(cohort domain.CohortName, nuid domain.NUID) (ActivityDB, error) {
+	var activity ActivityDB
+	err := s.Conn.Get(&activity, applicantActivity+"WHERE a.cohort = $1 AND a.nuid = $2;", cohort, nuid)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ActivityDB{}, ErrNotFound
+	} else if err != nil {
+		return ActivityDB{}, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return activity, nil
+}
+
+func (s *AdminStorage) CorrectActivity(cohort domain.CohortName) ([]ActivityDB, error) {
+	var activity []ActivityDB
+	err := s.Conn.Select(&activity, applicantActivity+"WHERE a.cohort = $1 AND c.first_correct IS NOT NULL;", cohort)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return activity, nil
+}
+
+func (s *ApplicantStorage) recordChallengeFetch(cohort string, nuid string, fetch ChallengeFetch, fetchedAt time.Time) error {
+	_, err := s.Conn.Exec("INSERT INTO challenge_fetches (cohort, nuid, fetched_at, ip, user_agent) VALUES ($1, $2, $3, $4, $5);", cohort, nuid, fetchedAt, nullString(fetch.IP), nullString(fetch.UserAgent))
+	return err
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrCohortNotFound = errors.New("cohort not found")
+	ErrCohortExists   = errors.New("cohort already exists")
+	ErrCohortClosed   = errors.New("cohort is not open for registration")
+)
+
+type CohortRepository interface {
+	CreateCohort(cohort CohortDB) error
+	Cohort(name domain.CohortName) (CohortDB, error)
+	Cohorts() ([]CohortDB, error)
+	CloseCohort(name domain.CohortName, closedAt time.Time) (CohortDB, error)
+	UpdateCohortChallenge(name domain.CohortName, challengeType string, challengeVersion int) error
+}
+
+type CohortSettings struct {
+	MaxAttempts        *int   `json:"max_attempts,omitempty"`
+	MinAttemptInterval string `json:"min_attempt_interval,omitempty"`
+	CloseTime          string `json:"close_time,omitempty"`
+	TimeBudget         string `json:"time_budget,omitempty"`
+	LateSubmissions    string `json:"late_submissions,omitempty"`
+	RevealScore        *bool  `json:"reveal_score,omitempty"`
+}
+
+func (s CohortSettings) Value() (driver.Value, error) {
+	settings, err := json.Marshal(s)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(settings), nil
+}
+
+func (s *CohortSettings) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*s = CohortSettings{}
+		return nil
+	case []byte:
+		return json.Unmarshal(src, s)
+	case string:
+		return json.Unmarshal([]byte(src), s)
+	default:
+		return fmt.Errorf("invalid cohort settings: unsupported type %T", src)
+	}
+}
+
+func (s CohortSettings) Apply(settings config.ChallengeSettings) (config.ChallengeSettings, error) {
+	if s.MaxAttempts != nil {
+		settings.MaxAttempts = *s.MaxAttempts
+	}
+
+	if s.MinAttemptInterval != "" {
+		minAttemptInterval, err := time.ParseDuration(s.MinAttemptInterval)
+
+		if err != nil {
+			return settings, fmt.Errorf("invalid min_attempt_interval %s", s.MinAttemptInterval)
+		}
+
+		settings.MinAttemptInterval = minAttemptInterval
+	}
+
+	if s.CloseTime != "" {
+		if _, err := time.Parse(time.RFC3339, s.CloseTime); err != nil {
+			return settings, fmt.Errorf("invalid close_time %s", s.CloseTime)
+		}
+
+		settings.CloseTime = s.CloseTime
+	}
+
+	if s.TimeBudget != "" {
+		timeBudget, err := time.ParseDuration(s.TimeBudget)
+
+		if err != nil {
+			return settings, fmt.Errorf("invalid time_budget %s", s.TimeBudget)
+		}
+
+		settings.TimeBudget = timeBudget
+	}
+
+	switch s.LateSubmissions {
+	case "":
+	case config.LateSubmissionsReject, config.LateSubmissionsFlag:
+		settings.LateSubmissions = s.LateSubmissions
+	default:
+		return settings, fmt.Errorf("invalid late_submissions %s", s.LateSubmissions)
+	}
+
+	if s.RevealScore != nil {
+		settings.RevealScore = *s.RevealScore
+	}
+
+	return settings, nil
+}
+
+type CohortDB struct {
+	Name             string         `db:"name"`
+	OpensAt          time.Time      `db:"opens_at"`
+	ClosesAt         sql.NullTime   `db:"closes_at"`
+	ClosedAt         sql.NullTime   `db:"closed_at"`
+	ChallengeType    string         `db:"challenge_type"`
+	ChallengeVersion int            `db:"challenge_version"`
+	Settings         CohortSettings `db:"settings"`
+	CreatedAt        time.Time      `db:"created_at"`
+}
+
+func (c CohortDB) OpenAt(now time.Time) bool {
+	if c.ClosedAt.Valid || now.Before(c.OpensAt) {
+		return false
+	}
+
+	return !c.ClosesAt.Valid || now.Before(c.ClosesAt.Time)
+}
+
+func registrationCohort(challenges *domain.ChallengeRegistry, cohort CohortDB, now time.Time) (domain.ChallengeGenerator, error) {
+	if !cohort.OpenAt(now) {
+		return nil, ErrCohortClosed
+	}
+
+	return challenges.Get(cohort.ChallengeType, cohort.ChallengeVersion)
+}
+
+func SyncDefaultCohort(cohorts CohortRepository, generator domain.ChallengeGenerator, now time.Time) error {
+	err := cohorts.UpdateCohortChallenge(domain.DefaultCohortName, generator.Name(), generator.Version())
+
+	if !errors.Is(err, ErrCohortNotFound) {
+		return err
+	}
+
+	err = cohorts.CreateCohort(CohortDB{
+		Name:             domain.DefaultCohortName.String(),
+		OpensAt:          now,
+		ChallengeType:    generator.Name(),
+		ChallengeVersion: generator.Version(),
+		CreatedAt:        now,
+	})
+
+	if errors.Is(err, ErrCohortExists) {
+		return cohorts.UpdateCohortChallenge(domain.DefaultCohortName, generator.Name(), generator.Version())
+	}
+
+	return err
+}
+
+const cohortColumns = "name, opens_at, closes_at, closed_at, challenge_type, challenge_version, settings, created_at"
+
+type CohortStorage struct {
+	Conn *sqlx.DB
+}
+
+func NewCohortStorage(conn *sqlx.DB) *CohortStorage {
+	return &CohortStorage{Conn: conn}
+}
+
+func (s *CohortStorage) CreateCohort(cohort CohortDB) error {
+	insertStatement := "INSERT INTO cohorts (" + cohortColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8);"
+	_, err := s.Conn.Exec(insertStatement, cohort.Name, cohort.OpensAt, cohort.ClosesAt, cohort.ClosedAt, cohort.ChallengeType, cohort.ChallengeVersion, cohort.Settings, cohort.CreatedAt)
+
+	if pgErr, isPGError := err.(*pq.Error); isPGError && pgErr.Code == "23505" {
+		return ErrCohortExists
+	}
+
+	return err
+}
+
+func (s *CohortStorage) Cohort(name domain.CohortName) (CohortDB, error) {
+	var cohort CohortDB
+	err := s.Conn.Get(&cohort, "SELECT "+cohortColumns+" FROM cohorts WHERE name = $1;", name)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return CohortDB{}, ErrCohortNotFound
+	} else if err != nil {
+		return CohortDB{}, err
+	}
+
+	return cohort, nil
+}
+
+func (s *CohortStorage) Cohorts() ([]CohortDB, error) {
+	cohorts := []CohortDB{}
+	err := s.Conn.Select(&cohorts, "SELECT "+cohortColumns+" FROM cohorts ORDER BY opens_at ASC, name ASC;")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return cohorts, nil
+}
+
+func (s *CohortStorage) CloseCohort(name domain.CohortName, closedAt time.Time) (CohortDB, error) {
+	var cohort CohortDB
+	err := s.Conn.Get(&cohort, "UPDATE cohorts SET closed_at = COALESCE(closed_at, $1) WHERE name = $2 RETURNING "+cohortColumns+";", closedAt, name)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return CohortDB{}, ErrCohortNotFound
+	} else if err != nil {
+		return CohortDB{}, err
+	}
+
+	return cohort, nil
+}
+
+func (s *CohortStorage) UpdateCohortChallenge(name domain.CohortName, challengeType string, challengeVersion int) error {
+	result, err := s.Conn.Exec("UPDATE cohorts SET challenge_type = $1, challenge_version = $2 WHERE name = $3;", challengeType, challengeVersion, name)
+
+	if err != nil {
+		return err
+	}
+
+	return cohortUpdated(result)
+}
+
+func cohortUpdated(result sql.Result) error {
+	updated, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return ErrCohortNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+)
+
+type ExportRowDB struct {
+	ApplicantDB
+	Attempts int64 `db:"attempts"`
+}
+
+func (s *AdminStorage) ExportApplicants(cohort domain.CohortName, fn func(ExportRowDB) error) error {
+	rows, err := s.Conn.Queryx(`
+	SELECT l.*, COALESCE(c.attempts, 0) AS attempts
+	FROM (`+applicantsWithLatestSubmission+`WHERE a.cohort = $1) l
+	LEFT JOIN (
+		SELECT cohort, nuid, COUNT(*) AS attempts
+		FROM submissions
+		GROUP BY cohort, nuid
+	) c ON l.cohort = c.cohort AND l.nuid = c.nuid
+	ORDER BY l.registration_time, l.nuid;
+`, cohort)
+
+	if err != nil {
+		return fmt.Errorf("failed to query database: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ExportRowDB
+
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("failed to scan row: %v", err)
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
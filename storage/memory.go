@@ -0,0 +1,963 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/google/uuid"
+)
+
+type memoryApplicantKey struct {
+	Cohort domain.CohortName
+	NUID   domain.NUID
+}
+
+type memoryApplicant struct {
+	Cohort           domain.CohortName
+	NUID             domain.NUID
+	Name             domain.ApplicantName
+	Email            domain.Email
+	RegistrationTime time.Time
+	TokenHash        string
+	PendingTokenHash string
+	TokenRevokedAt   *time.Time
+	Challenge        []string
+	Solution         []string
+	ChallengeType    string
+	ChallengeVersion int
+	Seed             int64
+	Fetches          []memoryFetch
+}
+
+type memoryFetch struct {
+	ChallengeFetch
+	FetchedAt time.Time
+}
+
+type memorySubmission struct {
+	SubmissionID   int64
+	Cohort         domain.CohortName
+	NUID           domain.NUID
+	Correct        bool
+	Score          int
+	Percentage     float64
+	SubmissionTime time.Time
+	Submission     []string
+	Late           bool
+}
+
+type memoryAPIKey struct {
+	APIKeyDB
+	KeyHash string
+}
+
+type MemoryStorage struct {
+	Challenges *domain.ChallengeRegistry
+	Generator  domain.ChallengeGenerator
+	Tokens     domain.TokenHasher
+
+	mu          sync.Mutex
+	cohorts     map[domain.CohortName]CohortDB
+	applicants  map[memoryApplicantKey]*memoryApplicant
+	submissions []memorySubmission
+	lastID      int64
+	apiKeys     []*memoryAPIKey
+	audit       []AdminAuditEntry
+	history     []TokenHistoryDB
+	lastHistory int64
+}
+
+func NewMemoryStorage(challenges *domain.ChallengeRegistry, generator domain.ChallengeGenerator, tokens domain.TokenHasher) *MemoryStorage {
+	createdAt := time.Now()
+
+	return &MemoryStorage{
+		Challenges: challenges,
+		Generator:  generator,
+		Tokens:     tokens,
+		cohorts: map[domain.CohortName]CohortDB{
+			domain.DefaultCohortName: {
+				Name:             domain.DefaultCohortName.String(),
+				OpensAt:          createdAt,
+				ChallengeType:    generator.Name(),
+				ChallengeVersion: generator.Version(),
+				CreatedAt:        createdAt,
+			},
+		},
+		applicants: make(map[memoryApplicantKey]*memoryApplicant),
+	}
+}
+
+func (applicant *memoryApplicant) key() memoryApplicantKey {
+	return memoryApplicantKey{Cohort: applicant.Cohort, NUID: applicant.NUID}
+}
+
+func copyStrings(strs []string) []string {
+	if strs == nil {
+		return nil
+	}
+
+	copied := make([]string, len(strs))
+	copy(copied, strs)
+	return copied
+}
+
+func (s *MemoryStorage) Register(applicant domain.Applicant) (RegisterResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryApplicantKey{Cohort: applicant.Cohort, NUID: applicant.NUID}
+
+	if _, exists := s.applicants[key]; exists {
+		return RegisterResult{}, ErrAlreadyRegistered
+	}
+
+	cohort, exists := s.cohorts[applicant.Cohort]
+
+	if !exists {
+		return RegisterResult{}, ErrCohortNotFound
+	}
+
+	registrationTime := time.Now()
+	generator, err := registrationCohort(s.Challenges, cohort, registrationTime)
+
+	if err != nil {
+		return RegisterResult{}, err
+	}
+
+	token := uuid.New()
+	seed := domain.GenerateSeed()
+	challenge := generator.Generate(seed)
+
+	s.applicants[key] = &memoryApplicant{
+		Cohort:           applicant.Cohort,
+		NUID:             applicant.NUID,
+		Name:             applicant.Name,
+		Email:            applicant.Email,
+		RegistrationTime: registrationTime,
+		TokenHash:        s.Tokens.Hash(token.String()),
+		Challenge:        challenge.Challenge,
+		Solution:         challenge.Solution,
+		ChallengeType:    generator.Name(),
+		ChallengeVersion: generator.Version(),
+		Seed:             seed,
+	}
+
+	s.recordTokenChange(key, TokenIssued, ApplicantActor, registrationTime)
+
+	return RegisterResult{Token: token, Challenge: copyStrings(challenge.Challenge)}, nil
+}
+
+func (s *MemoryStorage) recordTokenChange(key memoryApplicantKey, action TokenAction, actor string, changedAt time.Time) {
+	s.lastHistory++
+	s.history = append(s.history, TokenHistoryDB{
+		HistoryID: s.lastHistory,
+		Cohort:    sql.NullString{String: key.Cohort.String(), Valid: true},
+		NUID:      sql.NullString{String: key.NUID.String(), Valid: true},
+		Action:    action,
+		Actor:     actor,
+		ChangedAt: changedAt,
+	})
+}
+
+func (s *MemoryStorage) ForgotToken(cohort domain.CohortName, nuid domain.NUID) (ForgotTokenDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, exists := s.applicants[memoryApplicantKey{Cohort: cohort, NUID: nuid}]
+
+	if !exists || applicant.Email == "" || applicant.TokenRevokedAt != nil {
+		return ForgotTokenDB{}, ErrNotFound
+	}
+
+	token := uuid.New()
+	applicant.PendingTokenHash = s.Tokens.Hash(token.String())
+
+	return ForgotTokenDB{
+		Token:         sql.NullString{String: token.String(), Valid: true},
+		ApplicantName: sql.NullString{String: applicant.Name.String(), Valid: true},
+		Email:         sql.NullString{String: applicant.Email.String(), Valid: applicant.Email != ""},
+	}, nil
+}
+
+func (s *MemoryStorage) applicantByToken(token uuid.UUID) (*memoryApplicant, error) {
+	tokenHash := s.Tokens.Hash(token.String())
+
+	for _, applicant := range s.applicants {
+		if applicant.PendingTokenHash == tokenHash && applicant.TokenRevokedAt == nil {
+			applicant.TokenHash = applicant.PendingTokenHash
+			applicant.PendingTokenHash = ""
+
+			s.recordTokenChange(applicant.key(), TokenRecovered, ApplicantActor, time.Now())
+		}
+
+		if applicant.TokenHash != tokenHash {
+			continue
+		}
+
+		if applicant.TokenRevokedAt != nil {
+			return nil, ErrTokenRevoked
+		}
+
+		return applicant, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStorage) Challenge(token uuid.UUID, fetch ChallengeFetch) (ChallengeDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, err := s.applicantByToken(token)
+
+	if err != nil {
+		return ChallengeDB{}, err
+	}
+
+	applicant.Fetches = append(applicant.Fetches, memoryFetch{ChallengeFetch: fetch, FetchedAt: time.Now()})
+
+	return ChallengeDB{
+		Cohort:    sql.NullString{String: applicant.Cohort.String(), Valid: true},
+		NUID:      sql.NullString{String: applicant.NUID.String(), Valid: true},
+		Challenge: copyStrings(applicant.Challenge),
+	}, nil
+}
+
+func (s *MemoryStorage) Submit(token uuid.UUID, givenSolution []string) (SubmitDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, err := s.applicantByToken(token)
+
+	if err != nil {
+		return SubmitDB{}, err
+	}
+
+	return applicant.submitDB(), nil
+}
+
+func (applicant *memoryApplicant) submitDB() SubmitDB {
+	return SubmitDB{
+		Cohort:           sql.NullString{String: applicant.Cohort.String(), Valid: true},
+		NUID:             sql.NullString{String: applicant.NUID.String(), Valid: true},
+		Solution:         copyStrings(applicant.Solution),
+		ChallengeType:    sql.NullString{String: applicant.ChallengeType, Valid: true},
+		ChallengeVersion: sql.NullInt64{Int64: int64(applicant.ChallengeVersion), Valid: true},
+		RegistrationTime: sql.NullTime{Time: applicant.RegistrationTime, Valid: true},
+	}
+}
+
+func (s *MemoryStorage) WriteSubmit(nuid domain.NUID, submission SubmitDB, givenSolution []string, policy SubmissionPolicy) (SubmitOutcome, error) {
+	grade, err := gradeSubmission(s.Challenges, submission, givenSolution)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryApplicantKey{Cohort: domain.CohortName(submission.Cohort.String), NUID: nuid}
+
+	if _, exists := s.applicants[key]; !exists {
+		return SubmitOutcome{}, ErrNotFound
+	}
+
+	var lastSubmission sql.NullTime
+
+	if latest := s.latestSubmission(key); latest != nil {
+		lastSubmission = sql.NullTime{Time: latest.SubmissionTime, Valid: true}
+	}
+
+	attempts := s.attempts(key)
+	submissionTime := time.Now()
+
+	late, err := policy.Deadline.check(submission.RegistrationTime, submissionTime)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	if err := policy.Check(attempts, lastSubmission, submissionTime); err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	s.lastID++
+	s.submissions = append(s.submissions, memorySubmission{
+		SubmissionID:   s.lastID,
+		Cohort:         key.Cohort,
+		NUID:           nuid,
+		Correct:        grade.Correct(),
+		Score:          grade.Score,
+		Percentage:     grade.Percentage,
+		SubmissionTime: submissionTime,
+		Submission:     copyStrings(givenSolution),
+		Late:           late,
+	})
+
+	return policy.outcome(grade, attempts+1, late), nil
+}
+
+func (submission *memorySubmission) key() memoryApplicantKey {
+	return memoryApplicantKey{Cohort: submission.Cohort, NUID: submission.NUID}
+}
+
+func (s *MemoryStorage) latestSubmission(key memoryApplicantKey) *memorySubmission {
+	var latest *memorySubmission
+
+	for i := range s.submissions {
+		submission := &s.submissions[i]
+
+		if submission.key() != key {
+			continue
+		}
+
+		if latest == nil || !submission.SubmissionTime.Before(latest.SubmissionTime) {
+			latest = submission
+		}
+	}
+
+	return latest
+}
+
+func (s *MemoryStorage) firstCorrectSubmission(key memoryApplicantKey) (*memorySubmission, int) {
+	var attempts int
+
+	for i := range s.submissions {
+		submission := &s.submissions[i]
+
+		if submission.key() != key {
+			continue
+		}
+
+		if submission.Correct {
+			return submission, attempts
+		}
+
+		attempts++
+	}
+
+	return nil, 0
+}
+
+func (s *MemoryStorage) applicantDB(applicant *memoryApplicant) ApplicantDB {
+	applicantDB := ApplicantDB{
+		Cohort:           sql.NullString{String: applicant.Cohort.String(), Valid: true},
+		NUID:             sql.NullString{String: applicant.NUID.String(), Valid: true},
+		ApplicantName:    sql.NullString{String: applicant.Name.String(), Valid: true},
+		RegistrationTime: sql.NullTime{Time: applicant.RegistrationTime, Valid: true},
+	}
+
+	if latest := s.latestSubmission(applicant.key()); latest != nil {
+		applicantDB.Correct = sql.NullBool{Bool: latest.Correct, Valid: true}
+		applicantDB.Score = sql.NullInt64{Int64: int64(latest.Score), Valid: true}
+		applicantDB.Percentage = sql.NullFloat64{Float64: latest.Percentage, Valid: true}
+		applicantDB.SubmissionTime = sql.NullTime{Time: latest.SubmissionTime, Valid: true}
+		applicantDB.Late = sql.NullBool{Bool: latest.Late, Valid: true}
+	}
+
+	if first, attempts := s.firstCorrectSubmission(applicant.key()); first != nil {
+		applicantDB.FirstCorrectTime = sql.NullTime{Time: first.SubmissionTime, Valid: true}
+		applicantDB.AttemptsBeforeSuccess = sql.NullInt64{Int64: int64(attempts), Valid: true}
+	}
+
+	return applicantDB
+}
+
+func (s *MemoryStorage) sortedApplicants(cohort domain.CohortName) []*memoryApplicant {
+	var applicants []*memoryApplicant
+
+	for _, applicant := range s.applicants {
+		if applicant.Cohort == cohort {
+			applicants = append(applicants, applicant)
+		}
+	}
+
+	sort.Slice(applicants, func(i, j int) bool {
+		if applicants[i].RegistrationTime.Equal(applicants[j].RegistrationTime) {
+			return applicants[i].NUID < applicants[j].NUID
+		}
+
+		return applicants[i].RegistrationTime.Before(applicants[j].RegistrationTime)
+	})
+
+	return applicants
+}
+
+func (s *MemoryStorage) Applicant(cohort domain.CohortName, nuid domain.NUID) (ApplicantDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, exists := s.applicants[memoryApplicantKey{Cohort: cohort, NUID: nuid}]
+
+	if !exists {
+		return ApplicantDB{}, ErrNotFound
+	}
+
+	return s.applicantDB(applicant), nil
+}
+
+func memorySortKey(sort ApplicantSort, applicant ApplicantDB) float64 {
+	switch sort {
+	case SortTimeToCompletion:
+		if !applicant.SubmissionTime.Valid {
+			return math.Inf(1)
+		}
+
+		return applicant.SubmissionTime.Time.Sub(applicant.RegistrationTime.Time).Seconds()
+	default:
+		return float64(applicant.RegistrationTime.Time.UnixMicro()) / 1e6
+	}
+}
+
+func (s *MemoryStorage) Applicants(query ApplicantsQuery) ([]ApplicantPageDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var page []ApplicantPageDB
+
+	for _, applicant := range s.sortedApplicants(query.Cohort) {
+		applicantDB := s.applicantDB(applicant)
+
+		if query.Filter.Correct != nil && (!applicantDB.Correct.Valid || applicantDB.Correct.Bool != *query.Filter.Correct) {
+			continue
+		}
+
+		if query.Filter.Submitted != nil && applicantDB.SubmissionTime.Valid != *query.Filter.Submitted {
+			continue
+		}
+
+		if query.Filter.RegisteredAfter != nil && !applicant.RegistrationTime.After(*query.Filter.RegisteredAfter) {
+			continue
+		}
+
+		page = append(page, ApplicantPageDB{ApplicantDB: applicantDB, SortKey: memorySortKey(query.Sort, applicantDB)})
+	}
+
+	less := func(a ApplicantPageDB, sortKey float64, nuid string) bool {
+		if a.SortKey == sortKey {
+			return a.NUID.String < nuid
+		}
+
+		return a.SortKey < sortKey
+	}
+
+	sort.Slice(page, func(i, j int) bool {
+		if query.Descending {
+			return less(page[j], page[i].SortKey, page[i].NUID.String)
+		}
+
+		return less(page[i], page[j].SortKey, page[j].NUID.String)
+	})
+
+	if query.After != nil {
+		var remaining []ApplicantPageDB
+
+		for _, applicant := range page {
+			isAfter := less(ApplicantPageDB{ApplicantDB: ApplicantDB{NUID: sql.NullString{String: query.After.NUID, Valid: true}}, SortKey: query.After.SortKey}, applicant.SortKey, applicant.NUID.String)
+
+			if query.Descending {
+				isAfter = less(applicant, query.After.SortKey, query.After.NUID)
+			}
+
+			if isAfter {
+				remaining = append(remaining, applicant)
+			}
+		}
+
+		page = remaining
+	}
+
+	if len(page) > query.Limit {
+		page = page[:query.Limit]
+	}
+
+	return page, nil
+}
+
+func (s *MemoryStorage) submissionDB(submission memorySubmission) SubmissionDB {
+	submissionDB := SubmissionDB{
+		SubmissionID:   submission.SubmissionID,
+		Cohort:         sql.NullString{String: submission.Cohort.String(), Valid: true},
+		NUID:           sql.NullString{String: submission.NUID.String(), Valid: true},
+		Correct:        sql.NullBool{Bool: submission.Correct, Valid: true},
+		Score:          sql.NullInt64{Int64: int64(submission.Score), Valid: true},
+		Percentage:     sql.NullFloat64{Float64: submission.Percentage, Valid: true},
+		SubmissionTime: sql.NullTime{Time: submission.SubmissionTime, Valid: true},
+		Submission:     copyStrings(submission.Submission),
+		Late:           sql.NullBool{Bool: submission.Late, Valid: true},
+	}
+
+	if applicant, exists := s.applicants[submission.key()]; exists {
+		submissionDB.Solution = copyStrings(applicant.Solution)
+	}
+
+	return submissionDB
+}
+
+func (s *MemoryStorage) Submission(submissionID int64) (SubmissionDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, submission := range s.submissions {
+		if submission.SubmissionID == submissionID {
+			return s.submissionDB(submission), nil
+		}
+	}
+
+	return SubmissionDB{}, ErrNotFound
+}
+
+func (s *MemoryStorage) Submissions(cohort domain.CohortName, nuid domain.NUID) ([]SubmissionDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var submissions []SubmissionDB
+
+	for _, submission := range s.submissions {
+		if submission.Cohort == cohort && submission.NUID == nuid {
+			submissions = append(submissions, s.submissionDB(submission))
+		}
+	}
+
+	return submissions, nil
+}
+
+func (s *MemoryStorage) Counts(cohort domain.CohortName) (CountsDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var counts CountsDB
+
+	for _, submission := range s.submissions {
+		if submission.Cohort == cohort {
+			counts.Submissions++
+		}
+	}
+
+	for _, applicant := range s.sortedApplicants(cohort) {
+		counts.Registrations++
+
+		if latest := s.latestSubmission(applicant.key()); latest != nil {
+			counts.SubmittedApplicants++
+
+			if latest.Correct {
+				counts.CorrectApplicants++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func (s *MemoryStorage) attempts(key memoryApplicantKey) int {
+	attempts := 0
+
+	for _, submission := range s.submissions {
+		if submission.key() == key {
+			attempts++
+		}
+	}
+
+	return attempts
+}
+
+func (s *MemoryStorage) AttemptDistribution(cohort domain.CohortName) ([]AttemptCountDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicantsPerAttempts := make(map[int]int64)
+
+	for _, applicant := range s.sortedApplicants(cohort) {
+		applicantsPerAttempts[s.attempts(applicant.key())]++
+	}
+
+	var distribution []AttemptCountDB
+
+	for attempts, applicants := range applicantsPerAttempts {
+		distribution = append(distribution, AttemptCountDB{Attempts: attempts, Applicants: applicants})
+	}
+
+	sort.Slice(distribution, func(i, j int) bool {
+		return distribution[i].Attempts < distribution[j].Attempts
+	})
+
+	return distribution, nil
+}
+
+func (s *MemoryStorage) CorrectApplicants(cohort domain.CohortName) ([]ApplicantDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var applicants []ApplicantDB
+
+	for _, applicant := range s.sortedApplicants(cohort) {
+		applicantDB := s.applicantDB(applicant)
+
+		if applicantDB.Correct.Valid && applicantDB.Correct.Bool {
+			applicants = append(applicants, applicantDB)
+		}
+	}
+
+	return applicants, nil
+}
+
+func (s *MemoryStorage) activityDB(applicant *memoryApplicant) ActivityDB {
+	activity := ActivityDB{
+		Cohort:           sql.NullString{String: applicant.Cohort.String(), Valid: true},
+		NUID:             sql.NullString{String: applicant.NUID.String(), Valid: true},
+		RegistrationTime: sql.NullTime{Time: applicant.RegistrationTime, Valid: true},
+		Fetches:          int64(len(applicant.Fetches)),
+	}
+
+	for _, fetch := range applicant.Fetches {
+		if !activity.FirstFetch.Valid || fetch.FetchedAt.Before(activity.FirstFetch.Time) {
+			activity.FirstFetch = sql.NullTime{Time: fetch.FetchedAt, Valid: true}
+		}
+
+		if !activity.LastFetch.Valid || !fetch.FetchedAt.Before(activity.LastFetch.Time) {
+			activity.LastFetch = sql.NullTime{Time: fetch.FetchedAt, Valid: true}
+			activity.LastIP = nullString(fetch.IP)
+			activity.LastUserAgent = nullString(fetch.UserAgent)
+		}
+	}
+
+	for _, submission := range s.submissions {
+		if submission.key() != applicant.key() || !submission.Correct {
+			continue
+		}
+
+		if !activity.FirstCorrect.Valid || submission.SubmissionTime.Before(activity.FirstCorrect.Time) {
+			activity.FirstCorrect = sql.NullTime{Time: submission.SubmissionTime, Valid: true}
+		}
+	}
+
+	return activity
+}
+
+func (s *MemoryStorage) Activity(cohort domain.CohortName, nuid domain.NUID) (ActivityDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, exists := s.applicants[memoryApplicantKey{Cohort: cohort, NUID: nuid}]
+
+	if !exists {
+		return ActivityDB{}, ErrNotFound
+	}
+
+	return s.activityDB(applicant), nil
+}
+
+func (s *MemoryStorage) CorrectActivity(cohort domain.CohortName) ([]ActivityDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var activity []ActivityDB
+
+	for _, applicant := range s.sortedApplicants(cohort) {
+		applicantActivity := s.activityDB(applicant)
+
+		if applicantActivity.FirstCorrect.Valid {
+			activity = append(activity, applicantActivity)
+		}
+	}
+
+	return activity, nil
+}
+
+func (s *MemoryStorage) ExportApplicants(cohort domain.CohortName, fn func(ExportRowDB) error) error {
+	s.mu.Lock()
+
+	var rows []ExportRowDB
+
+	for _, applicant := range s.sortedApplicants(cohort) {
+		rows = append(rows, ExportRowDB{
+			ApplicantDB: s.applicantDB(applicant),
+			Attempts:    int64(s.attempts(applicant.key())),
+		})
+	}
+
+	s.mu.Unlock()
+
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStorage) DeleteApplicant(cohort domain.CohortName, nuid domain.NUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryApplicantKey{Cohort: cohort, NUID: nuid}
+
+	if _, exists := s.applicants[key]; !exists {
+		return ErrNotFound
+	}
+
+	delete(s.applicants, key)
+
+	submissions := s.submissions[:0]
+
+	for _, submission := range s.submissions {
+		if submission.key() != key {
+			submissions = append(submissions, submission)
+		}
+	}
+
+	s.submissions = submissions
+
+	history := s.history[:0]
+
+	for _, entry := range s.history {
+		if entry.Cohort.String != cohort.String() || entry.NUID.String != nuid.String() {
+			history = append(history, entry)
+		}
+	}
+
+	s.history = history
+
+	return nil
+}
+
+func (s *MemoryStorage) RotateToken(cohort domain.CohortName, nuid domain.NUID, actor string) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, exists := s.applicants[memoryApplicantKey{Cohort: cohort, NUID: nuid}]
+
+	if !exists {
+		return uuid.UUID{}, ErrNotFound
+	}
+
+	token := uuid.New()
+	applicant.TokenHash = s.Tokens.Hash(token.String())
+	applicant.PendingTokenHash = ""
+	applicant.TokenRevokedAt = nil
+
+	s.recordTokenChange(applicant.key(), TokenRotated, actor, time.Now())
+
+	return token, nil
+}
+
+func (s *MemoryStorage) RevokeToken(cohort domain.CohortName, nuid domain.NUID, actor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, exists := s.applicants[memoryApplicantKey{Cohort: cohort, NUID: nuid}]
+
+	if !exists {
+		return ErrNotFound
+	}
+
+	revokedAt := time.Now()
+	applicant.TokenRevokedAt = &revokedAt
+	applicant.PendingTokenHash = ""
+
+	s.recordTokenChange(applicant.key(), TokenRevoked, actor, revokedAt)
+
+	return nil
+}
+
+func (s *MemoryStorage) TokenHistory(cohort domain.CohortName, nuid domain.NUID) ([]TokenHistoryDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := []TokenHistoryDB{}
+
+	for _, entry := range s.history {
+		if entry.Cohort.String == cohort.String() && entry.NUID.String == nuid.String() {
+			history = append(history, entry)
+		}
+	}
+
+	if _, exists := s.applicants[memoryApplicantKey{Cohort: cohort, NUID: nuid}]; !exists {
+		return nil, ErrNotFound
+	}
+
+	return history, nil
+}
+
+func (s *MemoryStorage) Solution(cohort domain.CohortName, nuid domain.NUID) (SubmitDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applicant, exists := s.applicants[memoryApplicantKey{Cohort: cohort, NUID: nuid}]
+
+	if !exists {
+		return SubmitDB{}, ErrNotFound
+	}
+
+	return applicant.submitDB(), nil
+}
+
+func (s *MemoryStorage) HashLegacyTokens() (int, error) {
+	return 0, nil
+}
+
+func (s *MemoryStorage) Create(name string) (APIKeyDB, string, error) {
+	key, err := domain.GenerateAPIKey()
+
+	if err != nil {
+		return APIKeyDB{}, "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apiKey := &memoryAPIKey{
+		APIKeyDB: APIKeyDB{
+			APIKeyID:  int64(len(s.apiKeys) + 1),
+			Name:      name,
+			CreatedAt: time.Now(),
+		},
+		KeyHash: domain.HashAPIKey(key),
+	}
+
+	s.apiKeys = append(s.apiKeys, apiKey)
+
+	return apiKey.APIKeyDB, key, nil
+}
+
+func (s *MemoryStorage) Revoke(apiKeyID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, apiKey := range s.apiKeys {
+		if apiKey.APIKeyID == apiKeyID && !apiKey.RevokedAt.Valid {
+			apiKey.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no active API key with ID %d", apiKeyID)
+}
+
+func (s *MemoryStorage) List() ([]APIKeyDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apiKeys := make([]APIKeyDB, len(s.apiKeys))
+
+	for i, apiKey := range s.apiKeys {
+		apiKeys[i] = apiKey.APIKeyDB
+	}
+
+	return apiKeys, nil
+}
+
+func (s *MemoryStorage) Authenticate(key string) (APIKeyDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keyHash := domain.HashAPIKey(key)
+
+	for _, apiKey := range s.apiKeys {
+		if apiKey.KeyHash == keyHash && !apiKey.RevokedAt.Valid {
+			return apiKey.APIKeyDB, nil
+		}
+	}
+
+	return APIKeyDB{}, ErrNotFound
+}
+
+func (s *MemoryStorage) RecordAudit(entry AdminAuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.audit = append(s.audit, entry)
+
+	return nil
+}
+
+func (s *MemoryStorage) CreateCohort(cohort CohortDB) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := domain.CohortName(cohort.Name)
+
+	if _, exists := s.cohorts[name]; exists {
+		return ErrCohortExists
+	}
+
+	s.cohorts[name] = cohort
+
+	return nil
+}
+
+func (s *MemoryStorage) Cohort(name domain.CohortName) (CohortDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cohort, exists := s.cohorts[name]
+
+	if !exists {
+		return CohortDB{}, ErrCohortNotFound
+	}
+
+	return cohort, nil
+}
+
+func (s *MemoryStorage) Cohorts() ([]CohortDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cohorts := make([]CohortDB, 0, len(s.cohorts))
+
+	for _, cohort := range s.cohorts {
+		cohorts = append(cohorts, cohort)
+	}
+
+	sort.Slice(cohorts, func(i, j int) bool {
+		if cohorts[i].OpensAt.Equal(cohorts[j].OpensAt) {
+			return cohorts[i].Name < cohorts[j].Name
+		}
+
+		return cohorts[i].OpensAt.Before(cohorts[j].OpensAt)
+	})
+
+	return cohorts, nil
+}
+
+func (s *MemoryStorage) CloseCohort(name domain.CohortName, closedAt time.Time) (CohortDB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cohort, exists := s.cohorts[name]
+
+	if !exists {
+		return CohortDB{}, ErrCohortNotFound
+	}
+
+	if !cohort.ClosedAt.Valid {
+		cohort.ClosedAt = sql.NullTime{Time: closedAt, Valid: true}
+		s.cohorts[name] = cohort
+	}
+
+	return cohort, nil
+}
+
+func (s *MemoryStorage) UpdateCohortChallenge(name domain.CohortName, challengeType string, challengeVersion int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cohort, exists := s.cohorts[name]
+
+	if !exists {
+		return ErrCohortNotFound
+	}
+
+	cohort.ChallengeType = challengeType
+	cohort.ChallengeVersion = challengeVersion
+	s.cohorts[name] = cohort
+
+	return nil
+}
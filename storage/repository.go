@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/db"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+var (
+	ErrNotFound          = errors.New("record not found")
+	ErrAlreadyRegistered = errors.New("applicant has already registered")
+	ErrTokenRevoked      = errors.New("token has been revoked")
+)
+
+type ApplicantRepository interface {
+	Register(applicant domain.Applicant) (RegisterResult, error)
+	ForgotToken(cohort domain.CohortName, nuid domain.NUID) (ForgotTokenDB, error)
+	Challenge(token uuid.UUID, fetch ChallengeFetch) (ChallengeDB, error)
+	Submit(token uuid.UUID, givenSolution []string) (SubmitDB, error)
+	WriteSubmit(nuid domain.NUID, submission SubmitDB, givenSolution []string, policy SubmissionPolicy) (SubmitOutcome, error)
+}
+
+type AdminRepository interface {
+	Applicant(cohort domain.CohortName, nuid domain.NUID) (ApplicantDB, error)
+	Applicants(query ApplicantsQuery) ([]ApplicantPageDB, error)
+	Submission(submissionID int64) (SubmissionDB, error)
+	Submissions(cohort domain.CohortName, nuid domain.NUID) ([]SubmissionDB, error)
+	Counts(cohort domain.CohortName) (CountsDB, error)
+	AttemptDistribution(cohort domain.CohortName) ([]AttemptCountDB, error)
+	CorrectApplicants(cohort domain.CohortName) ([]ApplicantDB, error)
+	Activity(cohort domain.CohortName, nuid domain.NUID) (ActivityDB, error)
+	CorrectActivity(cohort domain.CohortName) ([]ActivityDB, error)
+	ExportApplicants(cohort domain.CohortName, fn func(ExportRowDB) error) error
+	DeleteApplicant(cohort domain.CohortName, nuid domain.NUID) error
+	RotateToken(cohort domain.CohortName, nuid domain.NUID, actor string) (uuid.UUID, error)
+	RevokeToken(cohort domain.CohortName, nuid domain.NUID, actor string) error
+	TokenHistory(cohort domain.CohortName, nuid domain.NUID) ([]TokenHistoryDB, error)
+	Solution(cohort domain.CohortName, nuid domain.NUID) (SubmitDB, error)
+	HashLegacyTokens() (int, error)
+}
+
+const legacyTokenLength = 36
+
+type legacyTokenDB struct {
+	Cohort string `db:"cohort"`
+	NUID   string `db:"nuid"`
+	Token  string `db:"token_hash"`
+}
+
+type APIKeyRepository interface {
+	Create(name string) (APIKeyDB, string, error)
+	Revoke(apiKeyID int64) error
+	List() ([]APIKeyDB, error)
+	Authenticate(key string) (APIKeyDB, error)
+	RecordAudit(entry AdminAuditEntry) error
+}
+
+type Repositories struct {
+	fx.Out
+
+	Applicants ApplicantRepository
+	Admin      AdminRepository
+	APIKeys    APIKeyRepository
+	Cohorts    CohortRepository
+}
+
+func OpenRepositories(settings config.Settings, challenges *domain.ChallengeRegistry, generator domain.ChallengeGenerator) (Repositories, func() error, error) {
+	if settings.Application.TokenHashKey == "" {
+		return Repositories{}, nil, fmt.Errorf("application.tokenhashkey must be set")
+	}
+
+	if _, err := domain.ParseCohortName(settings.Application.ActiveCohort().String()); err != nil {
+		return Repositories{}, nil, fmt.Errorf("application.cohort is invalid: %w", err)
+	}
+
+	tokens := domain.NewTokenHasher(settings.Application.TokenHashKey)
+
+	switch settings.Database.Driver {
+	case "", config.DriverPostgres:
+		conn, err := db.OpenPostgresConnection(settings)
+
+		if err != nil {
+			return Repositories{}, nil, err
+		}
+
+		return Repositories{
+			Applicants: NewApplicantStorage(conn, challenges, tokens),
+			Admin:      NewAdminStorage(conn, tokens),
+			APIKeys:    NewAPIKeyStorage(conn),
+			Cohorts:    NewCohortStorage(conn),
+		}, conn.Close, nil
+	case config.DriverSQLite:
+		conn, err := db.OpenSQLiteConnection(settings)
+
+		if err != nil {
+			return Repositories{}, nil, err
+		}
+
+		sqlite := NewSQLiteStorage(conn, challenges, tokens)
+
+		return Repositories{
+			Applicants: sqlite,
+			Admin:      sqlite,
+			APIKeys:    sqlite,
+			Cohorts:    sqlite,
+		}, conn.Close, nil
+	case config.DriverMemory:
+		memory := NewMemoryStorage(challenges, generator, tokens)
+
+		return Repositories{
+			Applicants: memory,
+			Admin:      memory,
+			APIKeys:    memory,
+			Cohorts:    memory,
+		}, func() error { return nil }, nil
+	default:
+		return Repositories{}, nil, fmt.Errorf("unknown database driver: %s", settings.Database.Driver)
+	}
+}
+
+func NewRepositories(lc fx.Lifecycle, settings config.Settings, challenges *domain.ChallengeRegistry, generator domain.ChallengeGenerator) (Repositories, error) {
+	repositories, closeRepositories, err := OpenRepositories(settings, challenges, generator)
+
+	if err != nil {
+		return Repositories{}, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if settings.Database.AutoMigrate && settings.Database.Driver != config.DriverMemory {
+				if err := db.Migrate(settings.Database); err != nil {
+					return err
+				}
+			}
+
+			if _, err := repositories.Admin.HashLegacyTokens(); err != nil {
+				return err
+			}
+
+			return SyncDefaultCohort(repositories.Cohorts, generator, time.Now())
+		},
+		OnStop: func(context.Context) error {
+			return closeRepositories()
+		},
+	})
+
+	return repositories, nil
+}
+
+func gradeSubmission(challenges *domain.ChallengeRegistry, submission SubmitDB, givenSolution []string) (domain.Grade, error) {
+	generator, err := challenges.Get(submission.ChallengeType.String, int(submission.ChallengeVersion.Int64))
+
+	if err != nil {
+		return domain.Grade{}, err
+	}
+
+	return generator.Grade(submission.Solution, givenSolution), nil
+}
@@ -0,0 +1,878 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+type SQLiteStorage struct {
+	Conn       *sqlx.DB
+	Challenges *domain.ChallengeRegistry
+	Tokens     domain.TokenHasher
+}
+
+func NewSQLiteStorage(conn *sqlx.DB, challenges *domain.ChallengeRegistry, tokens domain.TokenHasher) *SQLiteStorage {
+	return &SQLiteStorage{Conn: conn, Challenges: challenges, Tokens: tokens}
+}
+
+type JSONStringArray []string
+
+func (a *JSONStringArray) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, (*[]string)(a))
+	case string:
+		return json.Unmarshal([]byte(src), (*[]string)(a))
+	default:
+		return fmt.Errorf("invalid array format: unsupported type %T", src)
+	}
+}
+
+func jsonArray(strs []string) (string, error) {
+	if strs == nil {
+		strs = []string{}
+	}
+
+	encoded, err := json.Marshal(strs)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (s *SQLiteStorage) Register(applicant domain.Applicant) (RegisterResult, error) {
+	registrationTime := time.Now().UTC()
+	token := uuid.New()
+	seed := domain.GenerateSeed()
+
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return RegisterResult{}, err
+	}
+
+	defer tx.Rollback()
+
+	var cohort CohortDB
+	err = tx.Get(&cohort, "SELECT "+cohortColumns+" FROM cohorts WHERE name = ?;", applicant.Cohort)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return RegisterResult{}, ErrCohortNotFound
+	} else if err != nil {
+		return RegisterResult{}, err
+	}
+
+	generator, err := registrationCohort(s.Challenges, cohort, registrationTime)
+
+	if err != nil {
+		return RegisterResult{}, err
+	}
+
+	challenge := generator.Generate(seed)
+
+	challengeArray, err := jsonArray(challenge.Challenge)
+
+	if err != nil {
+		return RegisterResult{}, err
+	}
+
+	solutionArray, err := jsonArray(challenge.Solution)
+
+	if err != nil {
+		return RegisterResult{}, err
+	}
+
+	insertStatement := "INSERT INTO applicants (cohort, nuid, applicant_name, email, registration_time, token_hash, challenge, solution, challenge_type, challenge_version, seed) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	_, err = tx.Exec(insertStatement, applicant.Cohort, applicant.NUID, applicant.Name, applicant.Email, registrationTime, s.Tokens.Hash(token.String()), challengeArray, solutionArray, generator.Name(), generator.Version(), seed)
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return RegisterResult{}, ErrAlreadyRegistered
+	} else if err != nil {
+		return RegisterResult{}, err
+	}
+
+	if err := recordSQLiteTokenChange(tx, applicant.Cohort.String(), applicant.NUID.String(), TokenIssued, ApplicantActor, registrationTime); err != nil {
+		return RegisterResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return RegisterResult{}, err
+	}
+
+	return RegisterResult{Token: token, Challenge: challenge.Challenge}, nil
+}
+
+func recordSQLiteTokenChange(tx *sqlx.Tx, cohort string, nuid string, action TokenAction, actor string, changedAt time.Time) error {
+	_, err := tx.Exec("INSERT INTO token_history (cohort, nuid, action, actor, changed_at) VALUES (?, ?, ?, ?, ?);", cohort, nuid, action, actor, changedAt.UTC())
+	return err
+}
+
+func (s *SQLiteStorage) ForgotToken(cohort domain.CohortName, nuid domain.NUID) (ForgotTokenDB, error) {
+	token := uuid.New()
+
+	var dbResult ForgotTokenDB
+	err := s.Conn.Get(&dbResult, "UPDATE applicants SET pending_token_hash = ? WHERE cohort = ? AND nuid = ? AND email IS NOT NULL AND token_revoked_at IS NULL RETURNING applicant_name, email;", s.Tokens.Hash(token.String()), cohort, nuid)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ForgotTokenDB{}, ErrNotFound
+	} else if err != nil {
+		return ForgotTokenDB{}, err
+	}
+
+	dbResult.Token = sql.NullString{String: token.String(), Valid: true}
+
+	return dbResult, nil
+}
+
+func (s *SQLiteStorage) promotePendingToken(tokenHash string) error {
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var promoted pendingTokenDB
+	err = tx.Get(&promoted, "UPDATE applicants SET token_hash = pending_token_hash, pending_token_hash = NULL WHERE pending_token_hash = ? AND token_revoked_at IS NULL RETURNING cohort, nuid;", tokenHash)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := recordSQLiteTokenChange(tx, promoted.Cohort, promoted.NUID, TokenRecovered, ApplicantActor, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) Challenge(token uuid.UUID, fetch ChallengeFetch) (ChallengeDB, error) {
+	var dbResult struct {
+		Cohort         sql.NullString  `db:"cohort"`
+		NUID           sql.NullString  `db:"nuid"`
+		Challenge      JSONStringArray `db:"challenge"`
+		TokenRevokedAt sql.NullTime    `db:"token_revoked_at"`
+	}
+
+	tokenHash := s.Tokens.Hash(token.String())
+
+	if err := s.promotePendingToken(tokenHash); err != nil {
+		return ChallengeDB{}, err
+	}
+
+	err := s.Conn.Get(&dbResult, "SELECT cohort, nuid, challenge, token_revoked_at FROM applicants WHERE token_hash = ?;", tokenHash)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ChallengeDB{}, ErrNotFound
+	} else if err != nil {
+		return ChallengeDB{}, err
+	}
+
+	if dbResult.TokenRevokedAt.Valid {
+		return ChallengeDB{}, ErrTokenRevoked
+	}
+
+	_, err = s.Conn.Exec("INSERT INTO challenge_fetches (cohort, nuid, fetched_at, ip, user_agent) VALUES (?, ?, ?, ?, ?);", dbResult.Cohort, dbResult.NUID, time.Now().UTC(), nullString(fetch.IP), nullString(fetch.UserAgent))
+
+	if err != nil {
+		return ChallengeDB{}, err
+	}
+
+	return ChallengeDB{Cohort: dbResult.Cohort, NUID: dbResult.NUID, Challenge: StringArray(dbResult.Challenge), TokenRevokedAt: dbResult.TokenRevokedAt}, nil
+}
+
+func (s *SQLiteStorage) Submit(token uuid.UUID, givenSolution []string) (SubmitDB, error) {
+	tokenHash := s.Tokens.Hash(token.String())
+
+	if err := s.promotePendingToken(tokenHash); err != nil {
+		return SubmitDB{}, err
+	}
+
+	submission, err := s.submitDB("token_hash = ?", tokenHash)
+
+	if err != nil {
+		return SubmitDB{}, err
+	}
+
+	if submission.TokenRevokedAt.Valid {
+		return SubmitDB{}, ErrTokenRevoked
+	}
+
+	return submission, nil
+}
+
+func (s *SQLiteStorage) submitDB(condition string, args ...interface{}) (SubmitDB, error) {
+	var dbResult struct {
+		Cohort           sql.NullString  `db:"cohort"`
+		NUID             sql.NullString  `db:"nuid"`
+		Solution         JSONStringArray `db:"solution"`
+		ChallengeType    sql.NullString  `db:"challenge_type"`
+		ChallengeVersion sql.NullInt64   `db:"challenge_version"`
+		TokenRevokedAt   sql.NullTime    `db:"token_revoked_at"`
+		RegistrationTime sql.NullTime    `db:"registration_time"`
+	}
+	err := s.Conn.Get(&dbResult, fmt.Sprintf("SELECT cohort, nuid, solution, challenge_type, challenge_version, token_revoked_at, registration_time FROM applicants WHERE %s;", condition), args...)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return SubmitDB{}, ErrNotFound
+	} else if err != nil {
+		return SubmitDB{}, err
+	}
+
+	return SubmitDB{
+		Cohort:           dbResult.Cohort,
+		NUID:             dbResult.NUID,
+		Solution:         StringArray(dbResult.Solution),
+		ChallengeType:    dbResult.ChallengeType,
+		ChallengeVersion: dbResult.ChallengeVersion,
+		TokenRevokedAt:   dbResult.TokenRevokedAt,
+		RegistrationTime: dbResult.RegistrationTime,
+	}, nil
+}
+
+func (s *SQLiteStorage) WriteSubmit(nuid domain.NUID, submission SubmitDB, givenSolution []string, policy SubmissionPolicy) (SubmitOutcome, error) {
+	grade, err := gradeSubmission(s.Challenges, submission, givenSolution)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	givenArray, err := jsonArray(givenSolution)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	defer tx.Rollback()
+
+	var history attemptHistoryDB
+	err = tx.Get(&history.Attempts, "SELECT COUNT(*) FROM submissions WHERE cohort = ? AND nuid = ?;", submission.Cohort, nuid)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	err = tx.Get(&history.LastSubmission, "SELECT submission_time FROM submissions WHERE cohort = ? AND nuid = ? ORDER BY julianday(submission_time) DESC LIMIT 1;", submission.Cohort, nuid)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return SubmitOutcome{}, err
+	}
+
+	submissionTime := time.Now().UTC()
+
+	late, err := policy.Deadline.check(submission.RegistrationTime, submissionTime)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	if err := policy.Check(history.Attempts, history.LastSubmission, submissionTime); err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	insertStatement := "INSERT INTO submissions (cohort, nuid, correct, submission_time, score, percentage, submission, late) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	_, err = tx.Exec(insertStatement, submission.Cohort, nuid, grade.Correct(), submissionTime, grade.Score, grade.Percentage, givenArray, late)
+
+	if err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return SubmitOutcome{}, err
+	}
+
+	return policy.outcome(grade, history.Attempts+1, late), nil
+}
+
+const sqliteApplicantsWithLatestSubmission = `
+	SELECT a.cohort, a.nuid, a.applicant_name, s.correct, s.score, s.percentage, s.submission_time, a.registration_time, s.late,
+		   c.first_correct_time, c.attempts_before_success
+	FROM applicants a
+	LEFT JOIN (
+		SELECT cohort, nuid, correct, score, percentage, submission_time, late,
+			   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY julianday(submission_time) DESC, submission_id DESC) AS row_num
+		FROM submissions
+	) s ON a.cohort = s.cohort AND a.nuid = s.nuid AND s.row_num = 1
+	LEFT JOIN (
+		SELECT cohort, nuid, submission_time AS first_correct_time, attempt - 1 AS attempts_before_success
+		FROM (
+			SELECT cohort, nuid, correct, submission_time,
+				   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY julianday(submission_time), submission_id) AS attempt,
+				   ROW_NUMBER() OVER (PARTITION BY cohort, nuid, correct ORDER BY julianday(submission_time), submission_id) AS correct_rank
+			FROM submissions
+		) ranked
+		WHERE correct AND correct_rank = 1
+	) c ON a.cohort = c.cohort AND a.nuid = c.nuid
+`
+
+func sqliteEpoch(column string) string {
+	return fmt.Sprintf("((julianday(%s) - 2440587.5) * 86400.0)", column)
+}
+
+func (sort ApplicantSort) sqliteExpression() string {
+	switch sort {
+	case SortTimeToCompletion:
+		return fmt.Sprintf("COALESCE(%s - %s, 9e999)", sqliteEpoch("l.submission_time"), sqliteEpoch("l.registration_time"))
+	default:
+		return sqliteEpoch("l.registration_time")
+	}
+}
+
+func (s *SQLiteStorage) Applicant(cohort domain.CohortName, nuid domain.NUID) (ApplicantDB, error) {
+	var applicant ApplicantDB
+	err := s.Conn.Get(&applicant, sqliteApplicantsWithLatestSubmission+"WHERE a.cohort = ? AND a.nuid = ?;", cohort, nuid)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ApplicantDB{}, ErrNotFound
+	} else if err != nil {
+		return ApplicantDB{}, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return applicant, nil
+}
+
+func (s *SQLiteStorage) Applicants(query ApplicantsQuery) ([]ApplicantPageDB, error) {
+	conditions := []string{"cohort = ?"}
+	args := []interface{}{query.Cohort}
+
+	if query.Filter.Correct != nil {
+		conditions = append(conditions, "correct = ?")
+		args = append(args, *query.Filter.Correct)
+	}
+
+	if query.Filter.Submitted != nil {
+		if *query.Filter.Submitted {
+			conditions = append(conditions, "submission_time IS NOT NULL")
+		} else {
+			conditions = append(conditions, "submission_time IS NULL")
+		}
+	}
+
+	if query.Filter.RegisteredAfter != nil {
+		conditions = append(conditions, "julianday(registration_time) > julianday(?)")
+		args = append(args, query.Filter.RegisteredAfter.UTC())
+	}
+
+	comparator, direction := ">", "ASC"
+	if query.Descending {
+		comparator, direction = "<", "DESC"
+	}
+
+	if query.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(sort_key, nuid) %s (?, ?)", comparator))
+		args = append(args, query.After.SortKey, query.After.NUID)
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	statement := fmt.Sprintf(`
+	SELECT * FROM (
+		SELECT l.*, %s AS sort_key
+		FROM (%s) l
+	) k
+	%s
+	ORDER BY sort_key %s, nuid %s
+	LIMIT ?;
+`, query.Sort.sqliteExpression(), sqliteApplicantsWithLatestSubmission, where, direction, direction)
+
+	args = append(args, query.Limit)
+
+	var applicants []ApplicantPageDB
+	err := s.Conn.Select(&applicants, statement, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return applicants, nil
+}
+
+type sqliteSubmissionDB struct {
+	SubmissionID   int64           `db:"submission_id"`
+	Cohort         sql.NullString  `db:"cohort"`
+	NUID           sql.NullString  `db:"nuid"`
+	Correct        sql.NullBool    `db:"correct"`
+	Score          sql.NullInt64   `db:"score"`
+	Percentage     sql.NullFloat64 `db:"percentage"`
+	SubmissionTime sql.NullTime    `db:"submission_time"`
+	Submission     JSONStringArray `db:"submission"`
+	Solution       JSONStringArray `db:"solution"`
+	Late           sql.NullBool    `db:"late"`
+}
+
+func (submission sqliteSubmissionDB) submissionDB() SubmissionDB {
+	return SubmissionDB{
+		SubmissionID:   submission.SubmissionID,
+		Cohort:         submission.Cohort,
+		NUID:           submission.NUID,
+		Correct:        submission.Correct,
+		Score:          submission.Score,
+		Percentage:     submission.Percentage,
+		SubmissionTime: submission.SubmissionTime,
+		Submission:     StringArray(submission.Submission),
+		Solution:       StringArray(submission.Solution),
+		Late:           submission.Late,
+	}
+}
+
+func (s *SQLiteStorage) Submission(submissionID int64) (SubmissionDB, error) {
+	var submission sqliteSubmissionDB
+	err := s.Conn.Get(&submission, `
+	SELECT s.submission_id, s.cohort, s.nuid, s.correct, s.score, s.percentage, s.submission_time, s.submission, a.solution, s.late
+	FROM submissions s
+	JOIN applicants a ON a.cohort = s.cohort AND a.nuid = s.nuid
+	WHERE s.submission_id = ?;
+`, submissionID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return SubmissionDB{}, ErrNotFound
+	} else if err != nil {
+		return SubmissionDB{}, err
+	}
+
+	return submission.submissionDB(), nil
+}
+
+func (s *SQLiteStorage) Submissions(cohort domain.CohortName, nuid domain.NUID) ([]SubmissionDB, error) {
+	var dbResults []sqliteSubmissionDB
+	err := s.Conn.Select(&dbResults, `
+	SELECT s.submission_id, s.cohort, s.nuid, s.correct, s.score, s.percentage, s.submission_time, s.submission, a.solution, s.late
+	FROM submissions s
+	JOIN applicants a ON a.cohort = s.cohort AND a.nuid = s.nuid
+	WHERE s.cohort = ? AND s.nuid = ?
+	ORDER BY julianday(s.submission_time) ASC, s.submission_id ASC;
+`, cohort, nuid)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	submissions := make([]SubmissionDB, len(dbResults))
+
+	for i, submission := range dbResults {
+		submissions[i] = submission.submissionDB()
+	}
+
+	return submissions, nil
+}
+
+func (s *SQLiteStorage) Counts(cohort domain.CohortName) (CountsDB, error) {
+	var counts CountsDB
+	err := s.Conn.Get(&counts, `
+	SELECT
+		(SELECT COUNT(*) FROM applicants WHERE cohort = ?1) AS registrations,
+		(SELECT COUNT(*) FROM submissions WHERE cohort = ?1) AS submissions,
+		COUNT(l.submission_time) AS submitted_applicants,
+		COUNT(*) FILTER (WHERE l.correct) AS correct_applicants
+	FROM (`+sqliteApplicantsWithLatestSubmission+`WHERE a.cohort = ?1) l;
+`, cohort)
+
+	if err != nil {
+		return CountsDB{}, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return counts, nil
+}
+
+func (s *SQLiteStorage) AttemptDistribution(cohort domain.CohortName) ([]AttemptCountDB, error) {
+	var distribution []AttemptCountDB
+	err := s.Conn.Select(&distribution, `
+	SELECT attempts, COUNT(*) AS applicants
+	FROM (
+		SELECT a.nuid, COUNT(s.submission_id) AS attempts
+		FROM applicants a
+		LEFT JOIN submissions s ON a.cohort = s.cohort AND a.nuid = s.nuid
+		WHERE a.cohort = ?
+		GROUP BY a.nuid
+	) per_applicant
+	GROUP BY attempts
+	ORDER BY attempts;
+`, cohort)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return distribution, nil
+}
+
+func (s *SQLiteStorage) CorrectApplicants(cohort domain.CohortName) ([]ApplicantDB, error) {
+	var applicants []ApplicantDB
+	err := s.Conn.Select(&applicants, sqliteApplicantsWithLatestSubmission+"WHERE a.cohort = ? AND s.correct;", cohort)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return applicants, nil
+}
+
+const sqliteApplicantActivity = `
+	SELECT a.cohort, a.nuid, a.registration_time,
+		   (SELECT COUNT(*) FROM challenge_fetches WHERE cohort = a.cohort AND nuid = a.nuid) AS fetches,
+		   ff.fetched_at AS first_fetch, lf.fetched_at AS last_fetch,
+		   lf.ip AS last_ip, lf.user_agent AS last_user_agent,
+		   fc.submission_time AS first_correct
+	FROM applicants a
+	LEFT JOIN (
+		SELECT cohort, nuid, fetched_at,
+			   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY julianday(fetched_at) ASC, fetch_id ASC) AS row_num
+		FROM challenge_fetches
+	) ff ON a.cohort = ff.cohort AND a.nuid = ff.nuid AND ff.row_num = 1
+	LEFT JOIN (
+		SELECT cohort, nuid, fetched_at, ip, user_agent,
+			   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY julianday(fetched_at) DESC, fetch_id DESC) AS row_num
+		FROM challenge_fetches
+	) lf ON a.cohort = lf.cohort AND a.nuid = lf.nuid AND lf.row_num = 1
+	LEFT JOIN (
+		SELECT cohort, nuid, submission_time,
+			   ROW_NUMBER() OVER (PARTITION BY cohort, nuid ORDER BY julianday(submission_time) ASC, submission_id ASC) AS row_num
+		FROM submissions
+		WHERE correct
+	) fc ON a.cohort = fc.cohort AND a.nuid = fc.nuid AND fc.row_num = 1
+`
+
+func (s *SQLiteStorage) Activity(cohort domain.CohortName, nuid domain.NUID) (ActivityDB, error) {
+	var activity ActivityDB
+	err := s.Conn.Get(&activity, sqliteApplicantActivity+"WHERE a.cohort = ? AND a.nuid = ?;", cohort, nuid)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ActivityDB{}, ErrNotFound
+	} else if err != nil {
+		return ActivityDB{}, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return activity, nil
+}
+
+func (s *SQLiteStorage) CorrectActivity(cohort domain.CohortName) ([]ActivityDB, error) {
+	var activity []ActivityDB
+	err := s.Conn.Select(&activity, sqliteApplicantActivity+"WHERE a.cohort = ? AND fc.submission_time IS NOT NULL;", cohort)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return activity, nil
+}
+
+func (s *SQLiteStorage) ExportApplicants(cohort domain.CohortName, fn func(ExportRowDB) error) error {
+	rows, err := s.Conn.Queryx(`
+	SELECT l.*, COALESCE(c.attempts, 0) AS attempts
+	FROM (`+sqliteApplicantsWithLatestSubmission+`WHERE a.cohort = ?) l
+	LEFT JOIN (
+		SELECT cohort, nuid, COUNT(*) AS attempts
+		FROM submissions
+		GROUP BY cohort, nuid
+	) c ON l.cohort = c.cohort AND l.nuid = c.nuid
+	ORDER BY julianday(l.registration_time), l.nuid;
+`, cohort)
+
+	if err != nil {
+		return fmt.Errorf("failed to query database: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ExportRowDB
+
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("failed to scan row: %v", err)
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *SQLiteStorage) DeleteApplicant(cohort domain.CohortName, nuid domain.NUID) error {
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM submissions WHERE cohort = ? AND nuid = ?;", cohort, nuid); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM token_history WHERE cohort = ? AND nuid = ?;", cohort, nuid); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM challenge_fetches WHERE cohort = ? AND nuid = ?;", cohort, nuid); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM applicants WHERE cohort = ? AND nuid = ?;", cohort, nuid)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) RotateToken(cohort domain.CohortName, nuid domain.NUID, actor string) (uuid.UUID, error) {
+	token := uuid.New()
+
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	defer tx.Rollback()
+
+	if err := updateApplicant(tx, "UPDATE applicants SET token_hash = ?, pending_token_hash = NULL, token_revoked_at = NULL WHERE cohort = ? AND nuid = ?;", s.Tokens.Hash(token.String()), cohort, nuid); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if err := recordSQLiteTokenChange(tx, cohort.String(), nuid.String(), TokenRotated, actor, time.Now()); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return token, tx.Commit()
+}
+
+func (s *SQLiteStorage) RevokeToken(cohort domain.CohortName, nuid domain.NUID, actor string) error {
+	revokedAt := time.Now().UTC()
+
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := updateApplicant(tx, "UPDATE applicants SET token_revoked_at = ?, pending_token_hash = NULL WHERE cohort = ? AND nuid = ?;", revokedAt, cohort, nuid); err != nil {
+		return err
+	}
+
+	if err := recordSQLiteTokenChange(tx, cohort.String(), nuid.String(), TokenRevoked, actor, revokedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStorage) TokenHistory(cohort domain.CohortName, nuid domain.NUID) ([]TokenHistoryDB, error) {
+	history := []TokenHistoryDB{}
+	err := s.Conn.Select(&history, "SELECT history_id, cohort, nuid, action, actor, changed_at FROM token_history WHERE cohort = ? AND nuid = ? ORDER BY changed_at ASC, history_id ASC;", cohort, nuid)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	if len(history) == 0 {
+		if _, err := s.Solution(cohort, nuid); err != nil {
+			return nil, err
+		}
+	}
+
+	return history, nil
+}
+
+func (s *SQLiteStorage) Solution(cohort domain.CohortName, nuid domain.NUID) (SubmitDB, error) {
+	return s.submitDB("cohort = ? AND nuid = ?", cohort, nuid)
+}
+
+func (s *SQLiteStorage) HashLegacyTokens() (int, error) {
+	tx, err := s.Conn.Beginx()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	var legacy []legacyTokenDB
+	err = tx.Select(&legacy, "SELECT cohort, nuid, token_hash FROM applicants WHERE length(token_hash) = ?;", legacyTokenLength)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range legacy {
+		if _, err := tx.Exec("UPDATE applicants SET token_hash = ? WHERE cohort = ? AND nuid = ?;", s.Tokens.Hash(row.Token), row.Cohort, row.NUID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(legacy), tx.Commit()
+}
+
+func (s *SQLiteStorage) Create(name string) (APIKeyDB, string, error) {
+	key, err := domain.GenerateAPIKey()
+
+	if err != nil {
+		return APIKeyDB{}, "", err
+	}
+
+	createdAt := time.Now().UTC()
+	result, err := s.Conn.Exec("INSERT INTO api_keys (name, key_hash, created_at) VALUES (?, ?, ?);", name, domain.HashAPIKey(key), createdAt)
+
+	if err != nil {
+		return APIKeyDB{}, "", err
+	}
+
+	apiKeyID, err := result.LastInsertId()
+
+	if err != nil {
+		return APIKeyDB{}, "", err
+	}
+
+	return APIKeyDB{APIKeyID: apiKeyID, Name: name, CreatedAt: createdAt}, key, nil
+}
+
+func (s *SQLiteStorage) Revoke(apiKeyID int64) error {
+	result, err := s.Conn.Exec("UPDATE api_keys SET revoked_at = ? WHERE api_key_id = ? AND revoked_at IS NULL;", time.Now().UTC(), apiKeyID)
+
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("no active API key with ID %d", apiKeyID)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) List() ([]APIKeyDB, error) {
+	var apiKeys []APIKeyDB
+	err := s.Conn.Select(&apiKeys, "SELECT api_key_id, name, created_at, revoked_at FROM api_keys ORDER BY api_key_id;")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+func (s *SQLiteStorage) Authenticate(key string) (APIKeyDB, error) {
+	var apiKey APIKeyDB
+	err := s.Conn.Get(&apiKey, "SELECT api_key_id, name, created_at, revoked_at FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL;", domain.HashAPIKey(key))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIKeyDB{}, ErrNotFound
+	} else if err != nil {
+		return APIKeyDB{}, err
+	}
+
+	return apiKey, nil
+}
+
+func (s *SQLiteStorage) RecordAudit(entry AdminAuditEntry) error {
+	insertStatement := "INSERT INTO admin_audit_log (api_key_id, subject, method, path, status, request_time) VALUES (?, ?, ?, ?, ?, ?);"
+	_, err := s.Conn.Exec(insertStatement, entry.APIKeyID, entry.Subject, entry.Method, entry.Path, entry.Status, entry.RequestTime.UTC())
+
+	return err
+}
+
+func (s *SQLiteStorage) CreateCohort(cohort CohortDB) error {
+	insertStatement := "INSERT INTO cohorts (" + cohortColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
+	_, err := s.Conn.Exec(insertStatement, cohort.Name, cohort.OpensAt.UTC(), sqliteNullTime(cohort.ClosesAt), sqliteNullTime(cohort.ClosedAt), cohort.ChallengeType, cohort.ChallengeVersion, cohort.Settings, cohort.CreatedAt.UTC())
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return ErrCohortExists
+	}
+
+	return err
+}
+
+func sqliteNullTime(t sql.NullTime) sql.NullTime {
+	return sql.NullTime{Time: t.Time.UTC(), Valid: t.Valid}
+}
+
+func (s *SQLiteStorage) Cohort(name domain.CohortName) (CohortDB, error) {
+	var cohort CohortDB
+	err := s.Conn.Get(&cohort, "SELECT "+cohortColumns+" FROM cohorts WHERE name = ?;", name)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return CohortDB{}, ErrCohortNotFound
+	} else if err != nil {
+		return CohortDB{}, err
+	}
+
+	return cohort, nil
+}
+
+func (s *SQLiteStorage) Cohorts() ([]CohortDB, error) {
+	cohorts := []CohortDB{}
+	err := s.Conn.Select(&cohorts, "SELECT "+cohortColumns+" FROM cohorts ORDER BY julianday(opens_at) ASC, name ASC;")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return cohorts, nil
+}
+
+func (s *SQLiteStorage) CloseCohort(name domain.CohortName, closedAt time.Time) (CohortDB, error) {
+	_, err := s.Conn.Exec("UPDATE cohorts SET closed_at = ? WHERE name = ? AND closed_at IS NULL;", closedAt.UTC(), name)
+
+	if err != nil {
+		return CohortDB{}, err
+	}
+
+	return s.Cohort(name)
+}
+
+func (s *SQLiteStorage) UpdateCohortChallenge(name domain.CohortName, challengeType string, challengeVersion int) error {
+	result, err := s.Conn.Exec("UPDATE cohorts SET challenge_type = ?, challenge_version = ? WHERE name = ?;", challengeType, challengeVersion, name)
+
+	if err != nil {
+		return err
+	}
+
+	return cohortUpdated(result)
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+)
+
+type CountsDB struct {
+	Registrations       int64 `db:"registrations"`
+	Submissions         int64 `db:"submissions"`
+	SubmittedApplicants int64 `db:"submitted_applicants"`
+	CorrectApplicants   int64 `db:"correct_applicants"`
+}
+
+func (s *AdminStorage) Counts(cohort domain.CohortName) (CountsDB, error) {
+	var counts CountsDB
+	err := s.Conn.Get(&counts, `
+	SELECT
+		(SELECT COUNT(*) FROM applicants WHERE cohort = $1) AS registrations,
+		(SELECT COUNT(*) FROM submissions WHERE cohort = $1) AS submissions,
+		COUNT(l.submission_time) AS submitted_applicants,
+		COUNT(*) FILTER (WHERE l.correct) AS correct_applicants
+	FROM (`+applicantsWithLatestSubmission+`WHERE a.cohort = $1) l;
+`, cohort)
+
+	if err != nil {
+		return CountsDB{}, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return counts, nil
+}
+
+type AttemptCountDB struct {
+	Attempts   int   `db:"attempts"`
+	Applicants int64 `db:"applicants"`
+}
+
+func (s *AdminStorage) AttemptDistribution(cohort domain.CohortName) ([]AttemptCountDB, error) {
+	var distribution []AttemptCountDB
+	err := s.Conn.Select(&distribution, `
+	SELECT attempts, COUNT(*) AS applicants
+	FROM (
+		SELECT a.nuid, COUNT(s.submission_id) AS attempts
+		FROM applicants a
+		LEFT JOIN submissions s ON a.cohort = s.cohort AND a.nuid = s.nuid
+		WHERE a.cohort = $1
+		GROUP BY a.nuid
+	) per_applicant
+	GROUP BY attempts
+	ORDER BY attempts;
+`, cohort)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return distribution, nil
+}
+
+func (s *AdminStorage) CorrectApplicants(cohort domain.CohortName) ([]ApplicantDB, error) {
+	var applicants []ApplicantDB
+	err := s.Conn.Select(&applicants, applicantsWithLatestSubmission+"WHERE a.cohort = $1 AND s.correct;", cohort)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query database: %v", err)
+	}
+
+	return applicants, nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+)
+
+type SubmissionPolicy struct {
+	MaxAttempts        int
+	MinAttemptInterval time.Duration
+	Deadline           Deadline
+}
+
+type Deadline struct {
+	CloseTime  *time.Time
+	TimeBudget time.Duration
+	RejectLate bool
+}
+
+func (d Deadline) For(registrationTime sql.NullTime) *time.Time {
+	var deadline *time.Time
+
+	if d.CloseTime != nil {
+		closeTime := *d.CloseTime
+		deadline = &closeTime
+	}
+
+	if d.TimeBudget > 0 && registrationTime.Valid {
+		budget := registrationTime.Time.Add(d.TimeBudget)
+
+		if deadline == nil || budget.Before(*deadline) {
+			deadline = &budget
+		}
+	}
+
+	return deadline
+}
+
+func (d Deadline) check(registrationTime sql.NullTime, now time.Time) (bool, error) {
+	deadline := d.For(registrationTime)
+
+	if deadline == nil || !now.After(*deadline) {
+		return false, nil
+	}
+
+	if d.RejectLate {
+		return true, &LateSubmissionError{Deadline: *deadline}
+	}
+
+	return true, nil
+}
+
+type LateSubmissionError struct {
+	Deadline time.Time
+}
+
+func (e *LateSubmissionError) Error() string {
+	return fmt.Sprintf("submitted after the deadline of %s", e.Deadline.Format(time.RFC3339))
+}
+
+type AttemptLimitError struct {
+	Exhausted  bool
+	RetryAfter time.Duration
+}
+
+func (e *AttemptLimitError) Error() string {
+	if e.Exhausted {
+		return "no submission attempts remaining"
+	}
+
+	return fmt.Sprintf("submitted too soon, retry after %s", e.RetryAfter)
+}
+
+type SubmitOutcome struct {
+	Grade             domain.Grade
+	RemainingAttempts *int
+	Late              bool
+}
+
+func (p SubmissionPolicy) Check(attempts int, lastSubmission sql.NullTime, now time.Time) error {
+	if p.MaxAttempts > 0 && attempts >= p.MaxAttempts {
+		return &AttemptLimitError{Exhausted: true}
+	}
+
+	if p.MinAttemptInterval > 0 && lastSubmission.Valid {
+		if wait := lastSubmission.Time.Add(p.MinAttemptInterval).Sub(now); wait > 0 {
+			return &AttemptLimitError{RetryAfter: wait}
+		}
+	}
+
+	return nil
+}
+
+func (p SubmissionPolicy) outcome(grade domain.Grade, attempts int, late bool) SubmitOutcome {
+	outcome := SubmitOutcome{Grade: grade, Late: late}
+
+	if p.MaxAttempts > 0 {
+		remaining := p.MaxAttempts - attempts
+		outcome.RemainingAttempts = &remaining
+	}
+
+	return outcome
+}
+
+type attemptHistoryDB struct {
+	Attempts       int          `db:"attempts"`
+	LastSubmission sql.NullTime `db:"last_submission"`
+}
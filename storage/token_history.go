@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TokenAction string
+
+const (
+	TokenIssued    TokenAction = "issued"
+	TokenRecovered TokenAction = "recovered"
+	TokenRotated   TokenAction = "rotated"
+	TokenRevoked   TokenAction = "revoked"
+)
+
+const ApplicantActor = "applicant"
+
+type TokenHistoryDB struct {
+	HistoryID int64          `db:"history_id"`
+	Cohort    sql.NullString `db:"cohort"`
+	NUID      sql.NullString `db:"nuid"`
+	Action    TokenAction    `db:"action"`
+	Actor     string         `db:"actor"`
+	ChangedAt time.Time      `db:"changed_at"`
+}
+
+func recordTokenChange(tx *sqlx.Tx, cohort string, nuid string, action TokenAction, actor string, changedAt time.Time) error {
+	_, err := tx.Exec("INSERT INTO token_history (cohort, nuid, action, actor, changed_at) VALUES ($1, $2, $3, $4, $5);", cohort, nuid, action, actor, changedAt)
+	return err
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func getActivity(app TestApp, nuid string) (*handlers.ActivityResponse, int, error) {
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s/activity", app.Address, nuid), nil))
+
+	resp, err := app.App.Test(req)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, resp.StatusCode, nil
+	}
+
+	var activity handlers.ActivityResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&activity); err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return &activity, resp.StatusCode, nil
+}
+
+func TestActivity_RecordsChallengeFetches(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(settings *config.Settings) {
+				settings.Application.RateLimit.IPHeader = "do-connecting-ip"
+			})
+
+			assert.Nil(err)
+
+			registerResp, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			activity, status, err := getActivity(app, "002172052")
+
+			assert.Nil(err)
+			assert.Equal(200, status)
+			assert.Equal(int64(0), activity.Fetches)
+			assert.Nil(activity.FirstFetch)
+			assert.Nil(activity.FirstCorrect)
+			assert.Nil(activity.RegistrationToFirstCorrect)
+			assert.Nil(activity.FirstFetchToFirstCorrect)
+
+			for _, userAgent := range []string{"curl/8.0", "python-requests/2.31"} {
+				req := httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil)
+				req.Header.Set("User-Agent", userAgent)
+				req.Header.Set("do-connecting-ip", "203.0.113.7")
+
+				resp, err := app.App.Test(req)
+
+				assert.Nil(err)
+				assert.Equal(200, resp.StatusCode)
+			}
+
+			resp, err := SubmitSolution(app, registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			solution, err := CorrectSolution(registerResp)
+
+			assert.Nil(err)
+
+			resp, err = SubmitSolution(app, registerResp, solution)
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			activity, status, err = getActivity(app, "002172052")
+
+			assert.Nil(err)
+			assert.Equal(200, status)
+			assert.Equal(int64(2), activity.Fetches)
+			assert.NotNil(activity.FirstFetch)
+			assert.NotNil(activity.LastFetch)
+			assert.False(activity.LastFetch.Before(*activity.FirstFetch))
+			assert.Equal("203.0.113.7", activity.LastIP)
+			assert.Equal("python-requests/2.31", activity.LastUserAgent)
+			assert.NotNil(activity.FirstCorrect)
+			assert.NotNil(activity.RegistrationToFirstCorrect)
+			assert.NotNil(activity.FirstFetchToFirstCorrect)
+			assert.True(activity.FirstCorrect.Sub(activity.RegistrationTime) >= activity.FirstCorrect.Sub(*activity.FirstFetch))
+
+			req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/stats", app.Address), nil))
+
+			resp, err = app.App.Test(req)
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var stats handlers.StatsResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&stats))
+			assert.Contains(stats.RegistrationToFirstCorrect.Percentiles, "p50")
+			assert.Contains(stats.FirstFetchToFirstCorrect.Percentiles, "p50")
+		})
+	}
+}
+
+func TestActivity_UnknownApplicant(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	_, status, err := getActivity(app, "002172052")
+
+	assert.Nil(err)
+	assert.Equal(404, status)
+}
@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuth_ReturnsA401WithoutCredentials(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, "002172052"), nil)
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(401, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeAdminCredentialsMissing, problem.Code)
+
+	assert.Equal("missing admin credentials", problem.Detail)
+}
+
+func TestAdminAuth_ReturnsA401ForRevokedAPIKey(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	apiKeyStorage := storage.NewAPIKeyStorage(app.Conn)
+
+	apiKey, key, err := apiKeyStorage.Create("revoked")
+
+	assert.Nil(err)
+
+	err = apiKeyStorage.Revoke(apiKey.APIKeyID)
+
+	assert.Nil(err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, "002172052"), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(401, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeAdminCredentialsInvalid, problem.Code)
+
+	assert.Equal("invalid admin credentials", problem.Detail)
+}
+
+func TestAdminAuth_AcceptsSignedTokenAndRecordsAudit(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	token, err := domain.SignAdminToken([]byte("local-admin-token-secret"), "recruiter", time.Now().Add(time.Minute))
+
+	assert.Nil(err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, "002172052"), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(404, resp.StatusCode)
+
+	var audit struct {
+		Subject string `db:"subject"`
+		Path    string `db:"path"`
+		Status  int    `db:"status"`
+	}
+
+	err = app.Conn.Get(&audit, "SELECT subject, path, status FROM admin_audit_log;")
+
+	assert.Nil(err)
+
+	assert.Equal("token:recruiter", audit.Subject)
+	assert.Equal("/applicant/002172052", audit.Path)
+	assert.Equal(404, audit.Status)
+}
+
+type recordingAuditStorage struct {
+	storage.APIKeyRepository
+
+	entries []storage.AdminAuditEntry
+}
+
+func (r *recordingAuditStorage) RecordAudit(entry storage.AdminAuditEntry) error {
+	r.entries = append(r.entries, entry)
+
+	return nil
+}
+
+func TestAdminAuth_AuditEntriesKeepTheirPathAfterTheRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	configuration, err := loadConfiguration()
+
+	assert.Nil(err)
+
+	configuration.Database.Driver = config.DriverMemory
+
+	challenges := domain.DefaultChallengeRegistry()
+
+	generator, err := storage.NewChallengeGenerator(configuration, challenges)
+
+	assert.Nil(err)
+
+	repositories, _, err := storage.OpenRepositories(configuration, challenges, generator)
+
+	assert.Nil(err)
+
+	audit := &recordingAuditStorage{APIKeyRepository: repositories.APIKeys}
+
+	_, key, err := audit.Create("test")
+
+	assert.Nil(err)
+
+	authHandlers := handlers.NewAuthHandler(audit, configuration)
+
+	app := fiber.New()
+	app.Get("/admin/*", authHandlers.Admin, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, path := range []string{"/admin/first", "/admin/again"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+
+		resp, err := app.Test(req)
+
+		assert.Nil(err)
+		assert.Equal(200, resp.StatusCode)
+	}
+
+	assert.Equal(2, len(audit.entries))
+	assert.Equal("GET", audit.entries[0].Method)
+	assert.Equal("/admin/first", audit.entries[0].Path)
+	assert.Equal("/admin/again", audit.entries[1].Path)
+}
+
+func TestVerifyAdminToken_RejectsExpiredAndTamperedTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := []byte("secret")
+	now := time.Now()
+
+	token, err := domain.SignAdminToken(secret, "recruiter", now.Add(time.Minute))
+
+	assert.Nil(err)
+
+	claims, err := domain.VerifyAdminToken(secret, token, now)
+
+	assert.Nil(err)
+	assert.Equal("recruiter", claims.Subject)
+
+	claims, err = domain.VerifyAdminToken(secret, token, now.Add(time.Hour))
+
+	assert.EqualError(err, "admin token has expired")
+	assert.Nil(claims)
+
+	claims, err = domain.VerifyAdminToken([]byte("other"), token, now)
+
+	assert.EqualError(err, "invalid admin token signature")
+	assert.Nil(claims)
+}
+
+func TestHashAPIKey_IsStableAndDoesNotContainKey(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := domain.GenerateAPIKey()
+
+	assert.Nil(err)
+	assert.True(domain.IsAPIKey(key))
+	assert.Equal(domain.HashAPIKey(key), domain.HashAPIKey(key))
+	assert.NotContains(domain.HashAPIKey(key), key)
+}
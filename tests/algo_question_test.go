@@ -28,7 +28,7 @@ func TestGenerateChallenge(t *testing.T) {
 	}
 	nMandatory := len(mandatoryCases)
 	nRandom := 10
-	challenge := domain.GenerateChallenge(nRandom, mandatoryCases)
+	challenge := domain.GenerateChallenge(domain.GenerateSeed(), nRandom, mandatoryCases)
 
 	assert.Equal(nMandatory+nRandom, len(challenge.Challenge))
 
@@ -67,3 +67,18 @@ func TestOneEditAwayExample(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(domain.Green, *result)
 }
+
+func TestGenerateChallenge_IsDeterministicForSeed(t *testing.T) {
+	assert := assert.New(t)
+
+	red, _ := domain.Red.String()
+
+	challenge := domain.GenerateChallenge(42, 5, []string{"", red})
+
+	assert.Equal([]string{"red", "freen", "vozralnge", "syllow", "vijholet", "", ""}, challenge.Challenge)
+	assert.Equal([]string{"red", "green"}, challenge.Solution)
+
+	regenerated := domain.GenerateChallenge(42, 5, []string{"", red})
+
+	assert.Equal(challenge, regenerated)
+}
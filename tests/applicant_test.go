@@ -3,7 +3,6 @@ package tests
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http/httptest"
 	"testing"
 
@@ -38,7 +37,7 @@ func TestApplicant_ReturnsA200ForValidNUIDThatExistsWithCorrectSolution(t *testi
 
 	assert.Equal("Correct - nice work!", submitResponseBody.Message)
 
-	req := httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, nuid.String()), nil)
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, nuid.String()), nil))
 
 	resp, err := app.App.Test(req)
 
@@ -56,6 +55,10 @@ func TestApplicant_ReturnsA200ForValidNUIDThatExistsWithCorrectSolution(t *testi
 	assert.Equal("Garrett", applicantResponseBody.ApplicantName.String())
 
 	assert.True(applicantResponseBody.Correct)
+
+	assert.NotNil(applicantResponseBody.Percentage)
+
+	assert.Equal(100.0, *applicantResponseBody.Percentage)
 }
 
 func TestApplicant_ReturnsA200ForValidNUIDThatExistsWithIncorrectSolution(t *testing.T) {
@@ -87,7 +90,7 @@ func TestApplicant_ReturnsA200ForValidNUIDThatExistsWithIncorrectSolution(t *tes
 
 	assert.Equal("Incorrect Solution", submitResponseBody.Message)
 
-	req := httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, nuid.String()), nil)
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, nuid.String()), nil))
 
 	resp, err := app.App.Test(req)
 
@@ -116,7 +119,7 @@ func TestApplicant_ReturnsA400ForInvalidNUID(t *testing.T) {
 
 	badNUID := "foo"
 
-	req := httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, badNUID), nil)
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, badNUID), nil))
 
 	resp, err := app.App.Test(req)
 
@@ -124,13 +127,13 @@ func TestApplicant_ReturnsA400ForInvalidNUID(t *testing.T) {
 
 	assert.Equal(400, resp.StatusCode)
 
-	body, err := io.ReadAll(resp.Body)
+	problem, err := GetProblemFromResponse(resp)
 
 	assert.Nil(err)
 
-	responseString := string(body)
+	assert.Equal(handlers.CodeNUIDInvalid, problem.Code)
 
-	assert.Equal(fmt.Sprintf("invalid NUID %s", badNUID), responseString)
+	assert.Equal(fmt.Sprintf("invalid NUID %s", badNUID), problem.Detail)
 }
 
 func TestApplicant_ReturnsA404ForValidNUIDThatDoesNotExistInDB(t *testing.T) {
@@ -143,7 +146,34 @@ func TestApplicant_ReturnsA404ForValidNUIDThatDoesNotExistInDB(t *testing.T) {
 
 	assert.Nil(err)
 
-	req := httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, nonexistentNUID.String()), nil)
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, nonexistentNUID.String()), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(404, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeApplicantNotFound, problem.Code)
+
+	assert.Equal(fmt.Sprintf("Applicant with NUID %s not found!", nonexistentNUID.String()), problem.Detail)
+}
+
+func TestApplicant_ReturnsA404ForApplicantThatHasNotSubmitted(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/002172052", app.Address), nil))
 
 	resp, err := app.App.Test(req)
 
@@ -151,11 +181,11 @@ func TestApplicant_ReturnsA404ForValidNUIDThatDoesNotExistInDB(t *testing.T) {
 
 	assert.Equal(404, resp.StatusCode)
 
-	body, err := io.ReadAll(resp.Body)
+	problem, err := GetProblemFromResponse(resp)
 
 	assert.Nil(err)
 
-	responseString := string(body)
+	assert.Equal(handlers.CodeNotSubmitted, problem.Code)
 
-	assert.Equal(fmt.Sprintf("Applicant with NUID %s not found!", nonexistentNUID.String()), responseString)
+	assert.Equal("Applicant with NUID 002172052 has not submitted yet!", problem.Detail)
 }
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func getApplicants(app TestApp, query string) (*handlers.ApplicantsResponse, int, error) {
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicants?%s", app.Address, query), nil))
+
+	resp, err := app.App.Test(req)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, resp.StatusCode, nil
+	}
+
+	var responseBody handlers.ApplicantsResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return &responseBody, resp.StatusCode, nil
+}
+
+func TestApplicants_PaginatesWithCursor(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	nuids := []domain.NUID{"000000001", "000000002", "000000003"}
+
+	for _, nuid := range nuids {
+		_, err := RegisterSampleApplicantWithNUID(app, nuid)
+
+		assert.Nil(err)
+	}
+
+	firstPage, status, err := getApplicants(app, "limit=2")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(2, len(firstPage.Applicants))
+	assert.Equal(nuids[0], firstPage.Applicants[0].NUID)
+	assert.Equal(nuids[1], firstPage.Applicants[1].NUID)
+	assert.NotEmpty(firstPage.NextCursor)
+
+	secondPage, status, err := getApplicants(app, fmt.Sprintf("limit=2&cursor=%s", firstPage.NextCursor))
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(1, len(secondPage.Applicants))
+	assert.Equal(nuids[2], secondPage.Applicants[0].NUID)
+	assert.Empty(secondPage.NextCursor)
+
+	descending, status, err := getApplicants(app, "order=desc")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(nuids[2], descending.Applicants[0].NUID)
+}
+
+func TestApplicants_FiltersAndSortsByTimeToCompletion(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicantWithNUID(app, "000000001")
+
+	assert.Nil(err)
+
+	_, err = SubmitCorrectSolutionWithNUID(app, "000000002")
+
+	assert.Nil(err)
+
+	correct, status, err := getApplicants(app, "correct=true")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(1, len(correct.Applicants))
+	assert.Equal(domain.NUID("000000002"), correct.Applicants[0].NUID)
+	assert.True(correct.Applicants[0].Submitted)
+	assert.NotNil(correct.Applicants[0].TimeToCompletion)
+
+	notSubmitted, status, err := getApplicants(app, "submitted=false")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(1, len(notSubmitted.Applicants))
+	assert.Equal(domain.NUID("000000001"), notSubmitted.Applicants[0].NUID)
+	assert.Nil(notSubmitted.Applicants[0].TimeToCompletion)
+
+	sorted, status, err := getApplicants(app, "sort=time_to_completion")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(2, len(sorted.Applicants))
+	assert.Equal(domain.NUID("000000002"), sorted.Applicants[0].NUID)
+	assert.Equal(domain.NUID("000000001"), sorted.Applicants[1].NUID)
+}
+
+func TestApplicants_ReturnsA400ForInvalidQuery(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicants?sort=foo", app.Address), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(400, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeQueryInvalid, problem.Code)
+
+	assert.Equal("invalid sort foo", problem.Detail)
+}
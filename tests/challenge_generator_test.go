@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChallengeRegistry_ResolvesColorOneEditAway(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := domain.DefaultChallengeRegistry()
+
+	generator, err := registry.Get(domain.ColorOneEditAwayName, 1)
+
+	assert.Nil(err)
+	assert.Equal(domain.ColorOneEditAwayName, generator.Name())
+	assert.Equal(1, generator.Version())
+
+	latest, err := registry.Resolve(domain.ColorOneEditAwayName, 0)
+
+	assert.Nil(err)
+	assert.Equal(generator, latest)
+}
+
+func TestChallengeRegistry_RejectsUnknownChallenge(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := domain.DefaultChallengeRegistry()
+
+	generator, err := registry.Get("foo", 1)
+
+	assert.EqualError(err, "unknown challenge type: foo")
+	assert.Nil(generator)
+
+	generator, err = registry.Get(domain.ColorOneEditAwayName, 2)
+
+	assert.EqualError(err, "unknown version 2 of challenge type: color_one_edit_away")
+	assert.Nil(generator)
+}
+
+func TestColorOneEditAway_GradesGeneratedChallenge(t *testing.T) {
+	assert := assert.New(t)
+
+	generator := domain.NewColorOneEditAway()
+
+	challenge := generator.Generate(domain.GenerateSeed())
+
+	assert.Equal(generator.NRandom+len(generator.MandatoryCases), len(challenge.Challenge))
+	assert.Equal(challenge.Solution, generator.Solve(challenge.Challenge))
+	assert.True(generator.Grade(challenge.Solution, generator.Solve(challenge.Challenge)).Correct())
+	assert.False(generator.Grade(challenge.Solution, []string{}).Correct())
+}
+
+func TestChallengeRegistry_RegeneratesChallengeFromSeed(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := domain.DefaultChallengeRegistry()
+
+	seed := domain.GenerateSeed()
+
+	challenge := domain.NewColorOneEditAway().Generate(seed)
+
+	regenerated, err := registry.Regenerate(domain.ColorOneEditAwayName, 1, seed)
+
+	assert.Nil(err)
+	assert.Equal(challenge, regenerated)
+}
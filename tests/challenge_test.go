@@ -2,10 +2,10 @@ package tests
 
 import (
 	"fmt"
-	"io"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -59,13 +59,13 @@ func TestChallenge_ReturnsA400ForInvalidToken(t *testing.T) {
 
 	assert.Equal(400, challengeResp.StatusCode)
 
-	body, err := io.ReadAll(challengeResp.Body)
+	problem, err := GetProblemFromResponse(challengeResp)
 
 	assert.Nil(err)
 
-	responseString := string(body)
+	assert.Equal(handlers.CodeTokenInvalid, problem.Code)
 
-	assert.Equal(fmt.Sprintf("invalid token %s", invalidToken), responseString)
+	assert.Equal(fmt.Sprintf("invalid token %s", invalidToken), problem.Detail)
 }
 
 func TestChallenge_ReturnsA404ForTokenThatDoesNotExistInDB(t *testing.T) {
@@ -86,11 +86,11 @@ func TestChallenge_ReturnsA404ForTokenThatDoesNotExistInDB(t *testing.T) {
 
 	assert.Equal(404, challengeResp.StatusCode)
 
-	body, err := io.ReadAll(challengeResp.Body)
+	problem, err := GetProblemFromResponse(challengeResp)
 
 	assert.Nil(err)
 
-	responseString := string(body)
+	assert.Equal(handlers.CodeTokenNotFound, problem.Code)
 
-	assert.Equal(fmt.Sprintf("Record associated with token %s not found!", nonexistentToken), responseString)
+	assert.Equal(fmt.Sprintf("Record associated with token %s not found!", nonexistentToken), problem.Detail)
 }
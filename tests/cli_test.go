@@ -0,0 +1,279 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/cli"
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/db"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+)
+
+func sqliteCLISettings(t *testing.T) config.Settings {
+	configuration, err := loadConfiguration()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configuration.Database.Driver = config.DriverSQLite
+	configuration.Database.Path = filepath.Join(t.TempDir(), "challengeserver.db")
+
+	return configuration
+}
+
+func executeCLI(settings config.Settings, args ...string) (string, error) {
+	var out bytes.Buffer
+	err := cli.Execute(settings, args, &out)
+
+	return out.String(), err
+}
+
+func TestCLI_MigrateUpDownAndStatus(t *testing.T) {
+	assert := assert.New(t)
+	settings := sqliteCLISettings(t)
+
+	versions, err := db.MigrationVersions(settings.Database)
+
+	assert.Nil(err)
+
+	out, err := executeCLI(settings, "migrate", "status")
+
+	assert.Nil(err)
+	assert.Equal(len(versions), strings.Count(out, "pending"))
+
+	out, err = executeCLI(settings, "migrate", "up")
+
+	assert.Nil(err)
+	assert.Equal(len(versions), strings.Count(out, "applied"))
+
+	out, err = executeCLI(settings, "migrate", "down")
+
+	assert.Nil(err)
+	assert.Equal(1, strings.Count(out, "pending"))
+
+	_, err = executeCLI(settings, "migrate", "down", "all")
+
+	assert.Nil(err)
+
+	_, err = executeCLI(settings, "migrate", "sideways")
+
+	assert.NotNil(err)
+}
+
+func TestCLI_MigrateUpHashesLegacyTokens(t *testing.T) {
+	assert := assert.New(t)
+	settings := sqliteCLISettings(t)
+
+	m, err := db.NewMigrate(settings.Database)
+
+	assert.Nil(err)
+	assert.Nil(m.Migrate(20231113120000))
+
+	srcErr, dbErr := m.Close()
+
+	assert.Nil(srcErr)
+	assert.Nil(dbErr)
+
+	conn, err := db.OpenSQLiteConnection(settings)
+
+	assert.Nil(err)
+
+	legacyToken := uuid.New()
+
+	_, err = conn.Exec("INSERT INTO applicants (nuid, applicant_name, registration_time, token, challenge, solution) VALUES (?, ?, ?, ?, ?, ?);",
+		"002172052", "Garrett", time.Now().UTC(), legacyToken.String(), `["red"]`, `["rad"]`)
+
+	assert.Nil(err)
+	assert.Nil(conn.Close())
+
+	out, err := executeCLI(settings, "migrate", "up")
+
+	assert.Nil(err)
+	assert.Contains(out, "hashed 1 legacy tokens")
+
+	conn, err = db.OpenSQLiteConnection(settings)
+
+	assert.Nil(err)
+
+	defer conn.Close()
+
+	tokens := domain.NewTokenHasher(settings.Application.TokenHashKey)
+
+	var tokenHash string
+
+	assert.Nil(conn.Get(&tokenHash, "SELECT token_hash FROM applicants WHERE nuid = ?;", "002172052"))
+	assert.Equal(tokens.Hash(legacyToken.String()), tokenHash)
+
+	sqlite := storage.NewSQLiteStorage(conn, domain.DefaultChallengeRegistry(), tokens)
+
+	challenge, err := sqlite.Challenge(legacyToken, storage.ChallengeFetch{})
+
+	assert.Nil(err)
+	assert.Equal([]string{"red"}, []string(challenge.Challenge))
+
+	out, err = executeCLI(settings, "migrate", "up")
+
+	assert.Nil(err)
+	assert.NotContains(out, "hashed")
+}
+
+func TestRepositories_HashLegacyTokensOnStartWithoutAutoMigrate(t *testing.T) {
+	assert := assert.New(t)
+	settings := sqliteCLISettings(t)
+	settings.Database.AutoMigrate = false
+
+	m, err := db.NewMigrate(settings.Database)
+
+	assert.Nil(err)
+	assert.Nil(m.Migrate(20231113120000))
+
+	conn, err := db.OpenSQLiteConnection(settings)
+
+	assert.Nil(err)
+
+	legacyToken := uuid.New()
+
+	_, err = conn.Exec("INSERT INTO applicants (nuid, applicant_name, registration_time, token, challenge, solution) VALUES (?, ?, ?, ?, ?, ?);",
+		"002172052", "Garrett", time.Now().UTC(), legacyToken.String(), `["red"]`, `["rad"]`)
+
+	assert.Nil(err)
+	assert.Nil(m.Up())
+
+	srcErr, dbErr := m.Close()
+
+	assert.Nil(srcErr)
+	assert.Nil(dbErr)
+
+	challenges := domain.DefaultChallengeRegistry()
+	generator, err := storage.NewChallengeGenerator(settings, challenges)
+
+	assert.Nil(err)
+
+	lc := fxtest.NewLifecycle(t)
+	_, err = storage.NewRepositories(lc, settings, challenges, generator)
+
+	assert.Nil(err)
+
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	tokens := domain.NewTokenHasher(settings.Application.TokenHashKey)
+
+	var tokenHash string
+
+	assert.Nil(conn.Get(&tokenHash, "SELECT token_hash FROM applicants WHERE nuid = ?;", "002172052"))
+	assert.Equal(tokens.Hash(legacyToken.String()), tokenHash)
+	assert.Nil(conn.Close())
+}
+
+func TestCLI_ManagesApplicants(t *testing.T) {
+	assert := assert.New(t)
+	settings := sqliteCLISettings(t)
+
+	assert.Nil(db.Migrate(settings.Database))
+
+	out, err := executeCLI(settings, "seed", "-count", "3")
+
+	assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+
+	assert.Equal(3, len(lines))
+
+	fields := strings.Split(lines[0], "\t")
+	nuid := domain.NUID(fields[0])
+
+	out, err = executeCLI(settings, "applicant", "show", nuid.String())
+
+	assert.Nil(err)
+	assert.Contains(out, "submissions\t1")
+	assert.Contains(out, "correct=true")
+
+	out, err = executeCLI(settings, "token", "rotate", nuid.String())
+
+	assert.Nil(err)
+
+	token, err := uuid.Parse(strings.TrimSpace(out))
+
+	assert.Nil(err)
+	assert.NotEqual(fields[2], token.String())
+
+	conn, err := db.OpenSQLiteConnection(settings)
+
+	assert.Nil(err)
+
+	challenges := domain.DefaultChallengeRegistry()
+	sqlite := storage.NewSQLiteStorage(conn, challenges, domain.NewTokenHasher(settings.Application.TokenHashKey))
+
+	submission, err := sqlite.Submit(token, nil)
+
+	assert.Nil(err)
+
+	assert.Nil(conn.Close())
+
+	answers, err := json.Marshal(submission.Solution[1:])
+
+	assert.Nil(err)
+
+	answersPath := filepath.Join(t.TempDir(), "answers.json")
+
+	assert.Nil(os.WriteFile(answersPath, answers, 0o600))
+
+	out, err = executeCLI(settings, "grade", nuid.String(), answersPath)
+
+	assert.Nil(err)
+	assert.Contains(out, "correct=false")
+
+	out, err = executeCLI(settings, "export", "-format", "ndjson")
+
+	assert.Nil(err)
+	assert.Equal(3, strings.Count(out, "\n"))
+
+	_, err = executeCLI(settings, "applicant", "delete", nuid.String())
+
+	assert.Nil(err)
+
+	_, err = executeCLI(settings, "applicant", "show", nuid.String())
+
+	assert.NotNil(err)
+
+	_, err = executeCLI(settings, "token", "rotate", nuid.String())
+
+	assert.NotNil(err)
+}
+
+func TestCLI_CreatesAndRevokesAPIKeys(t *testing.T) {
+	assert := assert.New(t)
+	settings := sqliteCLISettings(t)
+
+	assert.Nil(db.Migrate(settings.Database))
+
+	out, err := executeCLI(settings, "apikey", "create", "recruiting")
+
+	assert.Nil(err)
+	assert.Contains(out, "created API key 1 (recruiting)")
+
+	_, err = executeCLI(settings, "apikey", "revoke", "1")
+
+	assert.Nil(err)
+
+	out, err = executeCLI(settings, "apikey", "list")
+
+	assert.Nil(err)
+	assert.Contains(out, "revoked")
+
+	_, err = executeCLI(settings, "apikey", "revoke", "1")
+
+	assert.NotNil(err)
+}
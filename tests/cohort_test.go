@@ -0,0 +1,366 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func createCohort(app TestApp, body map[string]interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("POST", fmt.Sprintf("%s/cohorts", app.Address), bytes.NewBuffer(data)))
+	req.Header.Set("Content-Type", "application/json")
+
+	return app.App.Test(req)
+}
+
+func registerInCohort(app TestApp, cohort string, nuid domain.NUID) (*http.Response, error) {
+	body, err := json.Marshal(map[string]string{
+		"name":  "Garrett",
+		"nuid":  nuid.String(),
+		"email": SampleEmail,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("%s/register?cohort=%s", app.Address, cohort), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	return app.App.Test(req)
+}
+
+func assertProblemCode(assert *assert.Assertions, resp *http.Response, status int, code handlers.ProblemCode) {
+	assert.Equal(status, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(code, problem.Code)
+}
+
+func TestCohort_CreatesListsAndClosesCohorts(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(*config.Settings) {})
+
+			assert.Nil(err)
+
+			resp, err := createCohort(app, map[string]interface{}{"name": "fall-2024"})
+
+			assert.Nil(err)
+			assert.Equal(201, resp.StatusCode)
+
+			var created handlers.CohortResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&created))
+			assert.Equal(domain.CohortName("fall-2024"), created.Name)
+			assert.True(created.Open)
+			assert.Equal("color_one_edit_away", created.ChallengeType)
+
+			resp, err = createCohort(app, map[string]interface{}{"name": "fall-2024"})
+
+			assert.Nil(err)
+			assertProblemCode(assert, resp, 409, handlers.CodeCohortExists)
+
+			resp, err = app.App.Test(AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/cohorts", app.Address), nil)))
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var cohorts []handlers.CohortResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&cohorts))
+
+			names := make([]domain.CohortName, len(cohorts))
+			for i, cohort := range cohorts {
+				names[i] = cohort.Name
+			}
+
+			assert.ElementsMatch([]domain.CohortName{domain.DefaultCohortName, "fall-2024"}, names)
+
+			resp, err = registerInCohort(app, "fall-2024", "002172052")
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			resp, err = app.App.Test(AuthorizeAdmin(app, httptest.NewRequest("POST", fmt.Sprintf("%s/cohorts/fall-2024/close", app.Address), nil)))
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var closed handlers.CohortResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&closed))
+			assert.False(closed.Open)
+			assert.NotNil(closed.ClosedAt)
+
+			resp, err = registerInCohort(app, "fall-2024", "002172053")
+
+			assert.Nil(err)
+			assertProblemCode(assert, resp, 403, handlers.CodeCohortClosed)
+
+			resp, err = app.App.Test(AuthorizeAdmin(app, httptest.NewRequest("POST", fmt.Sprintf("%s/cohorts/spring-2025/close", app.Address), nil)))
+
+			assert.Nil(err)
+			assertProblemCode(assert, resp, 404, handlers.CodeCohortNotFound)
+		})
+	}
+}
+
+func TestCohort_RegisterRejectsUnknownAndInvalidCohorts(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	resp, err := registerInCohort(app, "spring-2025", "002172052")
+
+	assert.Nil(err)
+	assertProblemCode(assert, resp, 404, handlers.CodeCohortNotFound)
+
+	resp, err = registerInCohort(app, "Spring!", "002172052")
+
+	assert.Nil(err)
+	assertProblemCode(assert, resp, 400, handlers.CodeCohortInvalid)
+
+	resp, err = createCohort(app, map[string]interface{}{"name": "-bad"})
+
+	assert.Nil(err)
+	assertProblemCode(assert, resp, 400, handlers.CodeCohortInvalid)
+
+	resp, err = createCohort(app, map[string]interface{}{"name": "spring-2025", "settings": map[string]string{"time_budget": "soon"}})
+
+	assert.Nil(err)
+	assertProblemCode(assert, resp, 400, handlers.CodeRequestBodyInvalid)
+}
+
+func TestCohort_KeysApplicantsByCohortAndNUID(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(*config.Settings) {})
+
+			assert.Nil(err)
+
+			resp, err := createCohort(app, map[string]interface{}{"name": "spring-2025"})
+
+			assert.Nil(err)
+			assert.Equal(201, resp.StatusCode)
+
+			_, err = SubmitCorrectSolution(app)
+
+			assert.Nil(err)
+
+			resp, err = registerInCohort(app, "spring-2025", "002172052")
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var registerResp handlers.RegisterResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&registerResp))
+
+			resp, err = SubmitSolution(app, &registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			resp, err = app.App.Test(AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/002172052", app.Address), nil)))
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var applicant handlers.ApplicantResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&applicant))
+			assert.True(applicant.Correct)
+
+			resp, err = app.App.Test(AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/002172052?cohort=spring-2025", app.Address), nil)))
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			applicant = handlers.ApplicantResponse{}
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&applicant))
+			assert.False(applicant.Correct)
+
+			resp, err = app.App.Test(AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/cohorts/compare", app.Address), nil)))
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var comparisons []handlers.CohortComparison
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&comparisons))
+
+			byCohort := make(map[domain.CohortName]handlers.CohortComparison)
+			for _, comparison := range comparisons {
+				byCohort[comparison.Cohort] = comparison
+			}
+
+			assert.Equal(int64(1), byCohort[domain.DefaultCohortName].Registrations)
+			assert.Equal(1.0, byCohort[domain.DefaultCohortName].PassRate)
+			assert.NotNil(byCohort[domain.DefaultCohortName].MedianTimeToFirstCorrect)
+			assert.Equal(0.0, *byCohort[domain.DefaultCohortName].MeanAttemptsBeforeSuccess)
+
+			assert.Equal(int64(1), byCohort["spring-2025"].Registrations)
+			assert.Equal(int64(1), byCohort["spring-2025"].Submissions)
+			assert.Equal(0.0, byCohort["spring-2025"].PassRate)
+			assert.Nil(byCohort["spring-2025"].MedianTimeToFirstCorrect)
+		})
+	}
+}
+
+func TestCohort_SettingsOverrideTheSubmissionPolicy(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(*config.Settings) {})
+
+			assert.Nil(err)
+
+			resp, err := createCohort(app, map[string]interface{}{"name": "strict", "settings": map[string]int{"max_attempts": 1}})
+
+			assert.Nil(err)
+			assert.Equal(201, resp.StatusCode)
+
+			resp, err = registerInCohort(app, "strict", "002172052")
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var registerResp handlers.RegisterResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&registerResp))
+
+			resp, err = SubmitSolution(app, &registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			resp, err = SubmitSolution(app, &registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assertProblemCode(assert, resp, 429, handlers.CodeAttemptsExhausted)
+
+			registerDefault, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			for i := 0; i < 2; i++ {
+				resp, err = SubmitSolution(app, registerDefault, []string{"wrong"})
+
+				assert.Nil(err)
+				assert.Equal(200, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestCohort_SettingsOverrideWhetherTheScoreIsRevealed(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(settings *config.Settings) {
+				settings.Challenge.RevealScore = false
+			})
+
+			assert.Nil(err)
+
+			for cohort, revealScore := range map[string]bool{"revealed": true, "hidden": false} {
+				resp, err := createCohort(app, map[string]interface{}{"name": cohort, "settings": map[string]bool{"reveal_score": revealScore}})
+
+				assert.Nil(err)
+				assert.Equal(201, resp.StatusCode)
+			}
+
+			for cohort, nuid := range map[string]domain.NUID{"revealed": "002172052", "hidden": "002172053"} {
+				resp, err := registerInCohort(app, cohort, nuid)
+
+				assert.Nil(err)
+				assert.Equal(200, resp.StatusCode)
+
+				var registerResp handlers.RegisterResponse
+
+				assert.Nil(json.NewDecoder(resp.Body).Decode(&registerResp))
+
+				resp, err = SubmitSolution(app, &registerResp, []string{"wrong"})
+
+				assert.Nil(err)
+				assert.Equal(200, resp.StatusCode)
+
+				var submitResponseBody handlers.SubmitResponseBody
+
+				assert.Nil(json.NewDecoder(resp.Body).Decode(&submitResponseBody))
+
+				assert.Equal(cohort == "revealed", submitResponseBody.NumCorrect != nil, "cohort %s", cohort)
+			}
+		})
+	}
+}
+
+type versionedGenerator struct {
+	domain.ChallengeGenerator
+	version int
+}
+
+func (g versionedGenerator) Version() int {
+	return g.version
+}
+
+func TestCohort_DefaultCohortFollowsTheConfiguredChallenge(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnSQLiteApp()
+
+	assert.Nil(err)
+
+	colorOneEditAway := domain.NewColorOneEditAway()
+	configured := versionedGenerator{ChallengeGenerator: colorOneEditAway, version: 2}
+	sqlite := storage.NewSQLiteStorage(app.Conn, domain.NewChallengeRegistry(colorOneEditAway, configured), app.Tokens)
+
+	_, err = app.Conn.Exec("DELETE FROM cohorts WHERE name = 'default';")
+
+	assert.Nil(err)
+	assert.Nil(storage.SyncDefaultCohort(sqlite, colorOneEditAway, time.Now()))
+
+	cohort, err := sqlite.Cohort(domain.DefaultCohortName)
+
+	assert.Nil(err)
+	assert.Equal(1, cohort.ChallengeVersion)
+
+	assert.Nil(storage.SyncDefaultCohort(sqlite, configured, time.Now()))
+
+	cohort, err = sqlite.Cohort(domain.DefaultCohortName)
+
+	assert.Nil(err)
+	assert.Equal(2, cohort.ChallengeVersion)
+
+	_, err = sqlite.Register(domain.Applicant{Cohort: domain.DefaultCohortName, NUID: "002172052", Name: "Garrett", Email: SampleEmail})
+
+	assert.Nil(err)
+
+	var challengeVersion int
+
+	assert.Nil(app.Conn.Get(&challengeVersion, "SELECT challenge_version FROM applicants WHERE nuid = '002172052';"))
+	assert.Equal(2, challengeVersion)
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadline_ForUsesTheEarlierOfCloseTimeAndTimeBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	registrationTime := time.Date(2023, time.September, 1, 12, 0, 0, 0, time.UTC)
+	registered := sql.NullTime{Time: registrationTime, Valid: true}
+	closeTime := time.Date(2023, time.September, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.Nil(storage.Deadline{}.For(registered))
+
+	assert.Equal(closeTime, *storage.Deadline{CloseTime: &closeTime}.For(registered))
+
+	assert.Equal(registrationTime.Add(72*time.Hour), *storage.Deadline{TimeBudget: 72 * time.Hour}.For(registered))
+
+	assert.Equal(registrationTime.Add(72*time.Hour), *storage.Deadline{CloseTime: &closeTime, TimeBudget: 72 * time.Hour}.For(registered))
+
+	assert.Equal(closeTime, *storage.Deadline{CloseTime: &closeTime, TimeBudget: 30 * 24 * time.Hour}.For(registered))
+
+	assert.Nil(storage.Deadline{TimeBudget: 72 * time.Hour}.For(sql.NullTime{}))
+}
+
+func TestDeadline_CloseAtParsesRFC3339(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := config.ChallengeSettings{CloseTime: "2023-12-01T00:00:00Z"}
+
+	assert.Equal(time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC), *settings.CloseAt())
+
+	settings.CloseTime = "December 1st"
+
+	assert.Nil(settings.CloseAt())
+
+	settings.CloseTime = ""
+
+	assert.Nil(settings.CloseAt())
+}
+
+func TestDeadline_ValidateRejectsMalformedSettings(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := config.ChallengeSettings{CloseTime: "2023-12-01T00:00:00Z", LateSubmissions: config.LateSubmissionsFlag}
+
+	assert.Nil(settings.Validate())
+
+	settings.CloseTime = "December 1st"
+
+	assert.NotNil(settings.Validate())
+
+	settings.CloseTime = ""
+	settings.LateSubmissions = "Flag"
+
+	assert.NotNil(settings.Validate())
+
+	settings.LateSubmissions = ""
+
+	assert.Nil(settings.Validate())
+}
+
+func TestDeadline_RejectsLateSubmissions(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(settings *config.Settings) {
+				settings.Challenge.TimeBudget = time.Nanosecond
+				settings.Challenge.LateSubmissions = config.LateSubmissionsReject
+			})
+
+			assert.Nil(err)
+
+			registerResp, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			resp, err := SubmitSolution(app, registerResp, registerResp.Challenge)
+
+			assert.Nil(err)
+			assert.Equal(403, resp.StatusCode)
+
+			problem, err := GetProblemFromResponse(resp)
+
+			assert.Nil(err)
+			assert.Equal(handlers.CodeDeadlinePassed, problem.Code)
+
+			req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/002172052/submissions", app.Address), nil))
+
+			resp, err = app.App.Test(req)
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var submissions []handlers.SubmissionResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&submissions))
+			assert.Empty(submissions)
+		})
+	}
+}
+
+func TestDeadline_FlagsLateSubmissions(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			closeTime := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+			app, err := spawn(func(settings *config.Settings) {
+				settings.Challenge.CloseTime = closeTime.Format(time.RFC3339)
+				settings.Challenge.LateSubmissions = config.LateSubmissionsFlag
+			})
+
+			assert.Nil(err)
+
+			registerResp, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			resp, err := SubmitSolution(app, registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var responseBody handlers.SubmitResponseBody
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&responseBody))
+			assert.True(responseBody.Late)
+
+			req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/002172052", app.Address), nil))
+
+			resp, err = app.App.Test(req)
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var applicant handlers.ApplicantResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&applicant))
+			assert.True(applicant.Late)
+			assert.NotNil(applicant.Deadline)
+			assert.True(closeTime.Equal(*applicant.Deadline))
+
+			req = AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/002172052/submissions", app.Address), nil))
+
+			resp, err = app.App.Test(req)
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var submissions []handlers.SubmissionResponse
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&submissions))
+			assert.Equal(1, len(submissions))
+			assert.True(submissions[0].Late)
+		})
+	}
+}
+
+func TestDeadline_AcceptsSubmissionsBeforeTheDeadline(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(settings *config.Settings) {
+				settings.Challenge.CloseTime = time.Now().Add(time.Hour).Format(time.RFC3339)
+				settings.Challenge.TimeBudget = 24 * time.Hour
+				settings.Challenge.LateSubmissions = config.LateSubmissionsReject
+			})
+
+			assert.Nil(err)
+
+			registerResp, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			resp, err := SubmitSolution(app, registerResp, registerResp.Challenge)
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var responseBody handlers.SubmitResponseBody
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&responseBody))
+			assert.False(responseBody.Late)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatISO8601Duration(t *testing.T) {
+	assert := assert.New(t)
+
+	for duration, expected := range map[time.Duration]string{
+		0:                      "PT0S",
+		500 * time.Millisecond: "PT0.5S",
+		time.Minute:            "PT1M",
+		26*time.Hour + 3*time.Minute + 4*time.Second + 500*time.Millisecond: "PT26H3M4.5S",
+		time.Hour + time.Nanosecond:                                         "PT1H0.000000001S",
+		-90 * time.Second:                                                   "-PT1M30S",
+	} {
+		assert.Equal(expected, domain.FormatISO8601Duration(duration))
+	}
+}
@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEmail_ValidEmail(t *testing.T) {
+	assert := assert.New(t)
+
+	result, err := domain.ParseEmail("garrett@example.com")
+
+	assert.Nil(err)
+	assert.Equal(domain.Email("garrett@example.com"), *result)
+}
+
+func TestParseEmail_InvalidEmailsAreRejected(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, email := range []string{
+		"",
+		" ",
+		"garrett",
+		"garrett@",
+		"@example.com",
+		"Garrett <garrett@example.com>",
+		" garrett@example.com",
+		"garrett@example.com\r\nBcc: someone@example.com",
+		strings.Repeat("a", 250) + "@example.com",
+	} {
+		result, err := domain.ParseEmail(email)
+
+		assert.NotNil(err, email)
+		assert.Nil(result, email)
+	}
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/export"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport_ReturnsCSVOfApplicants(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicantWithNUID(app, "000000001")
+
+	assert.Nil(err)
+
+	_, err = SubmitCorrectSolutionWithNUID(app, "000000002")
+
+	assert.Nil(err)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/export/applicants?format=csv", app.Address), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	assert.Equal("text/csv", resp.Header.Get("Content-Type"))
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+
+	assert.Nil(err)
+
+	assert.Equal(3, len(records))
+	assert.Equal("nuid", records[0][0])
+	assert.Equal("000000001", records[1][0])
+	assert.Equal("false", records[1][3])
+	assert.Equal("0", records[1][7])
+	assert.Equal("000000002", records[2][0])
+	assert.Equal("true", records[2][4])
+	assert.Equal("1", records[2][7])
+}
+
+func TestExport_EscapesFormulasInCSVNames(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]string{
+		`=HYPERLINK("http://example.com","click")`: `'=HYPERLINK("http://example.com","click")`,
+		"+1+1":         "'+1+1",
+		"-1+1":         "'-1+1",
+		"@SUM(A1)":     "'@SUM(A1)",
+		"Ada Lovelace": "Ada Lovelace",
+	}
+
+	for name, expected := range names {
+		var buffer bytes.Buffer
+
+		writer := export.NewWriter(export.FormatCSV, &buffer)
+
+		assert.Nil(writer.Write(export.Row{NUID: "002172052", ApplicantName: name}))
+		assert.Nil(writer.Flush())
+
+		records, err := csv.NewReader(&buffer).ReadAll()
+
+		assert.Nil(err)
+		assert.Equal(expected, records[1][1])
+	}
+}
+
+func TestExport_ReturnsNDJSONOfApplicants(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	_, err = SubmitCorrectSolutionWithNUID(app, "000000001")
+
+	assert.Nil(err)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/export/applicants?format=ndjson", app.Address), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	var rows []export.Row
+
+	for scanner.Scan() {
+		var row export.Row
+
+		assert.Nil(json.Unmarshal(scanner.Bytes(), &row))
+
+		rows = append(rows, row)
+	}
+
+	assert.Equal(1, len(rows))
+	assert.Equal("000000001", rows[0].NUID)
+	assert.True(rows[0].Correct)
+	assert.Equal(int64(1), rows[0].Attempts)
+	assert.NotNil(rows[0].TimeToCompletionSeconds)
+}
+
+func TestExport_ReturnsA400ForInvalidFormat(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/export/applicants?format=xlsx", app.Address), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(400, resp.StatusCode)
+}
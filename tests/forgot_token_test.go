@@ -1,16 +1,19 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
-	"io"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/mailer"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestForgot_Token_ReturnsA200ForNUIDThatExists(t *testing.T) {
+func TestForgot_Token_ReturnsA202AndEmailsTheTokenForNUIDThatExists(t *testing.T) {
 	assert := assert.New(t)
 	app, err := SpawnApp()
 
@@ -32,13 +35,99 @@ func TestForgot_Token_ReturnsA200ForNUIDThatExists(t *testing.T) {
 
 	assert.Nil(err)
 
-	assert.Equal(200, forgotTokenResp.StatusCode)
+	assert.Equal(202, forgotTokenResp.StatusCode)
 
-	token, err := GetTokenFromResponse(forgotTokenResp)
+	var responseBody map[string]interface{}
+
+	assert.Nil(json.NewDecoder(forgotTokenResp.Body).Decode(&responseBody))
+
+	assert.NotContains(responseBody, "token")
+
+	token, err := GetTokenFromOutbox(app, SampleEmail)
+
+	assert.Nil(err)
+
+	assert.NotEqual(registerResp.Token, *token)
+
+	var hashes struct {
+		TokenHash        string `db:"token_hash"`
+		PendingTokenHash string `db:"pending_token_hash"`
+	}
+
+	assert.Nil(app.Conn.Get(&hashes, "SELECT token_hash, pending_token_hash FROM applicants WHERE nuid=$1;", nuid.String()))
+
+	assert.Equal(app.Tokens.Hash(registerResp.Token.String()), hashes.TokenHash)
+	assert.Equal(app.Tokens.Hash(token.String()), hashes.PendingTokenHash)
+}
+
+func TestForgot_Token_KeepsTheCurrentTokenValidUntilTheNewTokenIsUsed(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnSQLiteApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/002172052", app.Address), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(202, resp.StatusCode)
+
+	token, err := GetTokenFromOutbox(app, SampleEmail)
+
+	assert.Nil(err)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(404, resp.StatusCode)
+
+	var recovered int
+
+	assert.Nil(app.Conn.Get(&recovered, "SELECT COUNT(*) FROM token_history WHERE nuid = ? AND action = 'recovered';", "002172052"))
+
+	assert.Equal(1, recovered)
+}
+
+func TestForgot_Token_ReturnsA202AndKeepsTheCurrentTokenWhenMailFails(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	assert.Nil(os.Mkdir(app.Outbox, 0o700))
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/002172052", app.Address), nil))
 
 	assert.Nil(err)
 
-	assert.Equal(&registerResp.Token, token)
+	assert.Equal(202, resp.StatusCode)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
 }
 
 func TestForgot_Token_ReturnssA400ForInvalidNUID(t *testing.T) {
@@ -59,16 +148,16 @@ func TestForgot_Token_ReturnssA400ForInvalidNUID(t *testing.T) {
 
 	assert.Equal(400, resp.StatusCode)
 
-	body, err := io.ReadAll(resp.Body)
+	problem, err := GetProblemFromResponse(resp)
 
 	assert.Nil(err)
 
-	responseString := string(body)
+	assert.Equal(handlers.CodeNUIDInvalid, problem.Code)
 
-	assert.Equal(fmt.Sprintf("invalid NUID %s", badNUID), responseString)
+	assert.Equal(fmt.Sprintf("invalid NUID %s", badNUID), problem.Detail)
 }
 
-func TestForgot_Token_ReturnssA400ForNUIDThatDoesNotExistInDB(t *testing.T) {
+func TestForgot_Token_ReturnsA202WithoutSendingMailForNUIDThatDoesNotExistInDB(t *testing.T) {
 	assert := assert.New(t)
 	app, err := SpawnApp()
 
@@ -84,13 +173,11 @@ func TestForgot_Token_ReturnssA400ForNUIDThatDoesNotExistInDB(t *testing.T) {
 
 	assert.Nil(err)
 
-	assert.Equal(404, resp.StatusCode)
+	assert.Equal(202, resp.StatusCode)
 
-	body, err := io.ReadAll(resp.Body)
+	messages, err := mailer.ReadOutbox(app.Outbox)
 
 	assert.Nil(err)
 
-	responseString := string(body)
-
-	assert.Equal(fmt.Sprintf("Applicant with NUID %s not found!", nonexistentNUID), responseString)
+	assert.Empty(messages)
 }
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradeCases_AllCorrect(t *testing.T) {
+	assert := assert.New(t)
+
+	grade := domain.GradeCases([]string{"red", "blue"}, []string{"red", "blue"})
+
+	assert.Equal([]domain.CaseResult{domain.CaseCorrect, domain.CaseCorrect}, grade.Results)
+	assert.Equal(2, grade.Score)
+	assert.Equal(100.0, grade.Percentage)
+	assert.True(grade.Correct())
+}
+
+func TestGradeCases_IncorrectAndMissing(t *testing.T) {
+	assert := assert.New(t)
+
+	grade := domain.GradeCases([]string{"red", "blue", "green", "violet"}, []string{"red", "green"})
+
+	assert.Equal([]domain.CaseResult{domain.CaseCorrect, domain.CaseIncorrect, domain.CaseMissing, domain.CaseMissing}, grade.Results)
+	assert.Equal(1, grade.Score)
+	assert.Equal(25.0, grade.Percentage)
+	assert.False(grade.Correct())
+}
+
+func TestGradeCases_ExtraAnswersCountAgainstPercentage(t *testing.T) {
+	assert := assert.New(t)
+
+	grade := domain.GradeCases([]string{"red"}, []string{"red", "blue"})
+
+	assert.Equal([]domain.CaseResult{domain.CaseCorrect, domain.CaseExtra}, grade.Results)
+	assert.Equal(1, grade.Score)
+	assert.Equal(50.0, grade.Percentage)
+	assert.False(grade.Correct())
+}
@@ -8,129 +8,229 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
 
 	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/db"
 	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
 	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/mailer"
+	"github.com/garrettladley/generate_coding_challenge_server_go/ratelimit"
 	"github.com/garrettladley/generate_coding_challenge_server_go/server"
 	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 type TestApp struct {
-	App     *fiber.App
-	Address string
-	Conn    *sqlx.DB
+	App      *fiber.App
+	Address  string
+	Conn     *sqlx.DB
+	AdminKey string
+	Outbox   string
+	Tokens   domain.TokenHasher
 }
 
 func SpawnApp() (TestApp, error) {
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	configuration, err := loadConfiguration()
 
 	if err != nil {
 		return TestApp{}, err
 	}
 
-	initialDir, err := os.Getwd()
+	configuration.Database.DatabaseName = generateRandomDBName()
+
+	connectionWithDB, err := configureDatabase(configuration.Database)
 
 	if err != nil {
 		return TestApp{}, err
 	}
 
-	err = os.Chdir("../")
+	tokens := domain.NewTokenHasher(configuration.Application.TokenHashKey)
+
+	app, err := spawnAppWithRepositories(configuration, storage.Repositories{
+		Applicants: storage.NewApplicantStorage(connectionWithDB, domain.DefaultChallengeRegistry(), tokens),
+		Admin:      storage.NewAdminStorage(connectionWithDB, tokens),
+		APIKeys:    storage.NewAPIKeyStorage(connectionWithDB),
+		Cohorts:    storage.NewCohortStorage(connectionWithDB),
+	})
 
 	if err != nil {
 		return TestApp{}, err
 	}
 
-	configuration, err := config.GetConfiguration()
+	app.Conn = connectionWithDB
+
+	return app, nil
+}
+
+func SpawnMemoryApp() (TestApp, error) {
+	return SpawnMemoryAppWith(func(*config.Settings) {})
+}
+
+func SpawnMemoryAppWith(configure func(*config.Settings)) (TestApp, error) {
+	configuration, err := loadConfiguration()
 
 	if err != nil {
 		return TestApp{}, err
 	}
 
-	err = os.Chdir(initialDir)
+	configure(&configuration)
+
+	configuration.Database.Driver = config.DriverMemory
+
+	challenges := domain.DefaultChallengeRegistry()
+
+	generator, err := storage.NewChallengeGenerator(configuration, challenges)
 
 	if err != nil {
 		return TestApp{}, err
 	}
 
-	configuration.Database.DatabaseName = generateRandomDBName()
-
-	connectionWithDB, err := configureDatabase(configuration.Database)
+	repositories, _, err := storage.OpenRepositories(configuration, challenges, generator)
 
 	if err != nil {
 		return TestApp{}, err
 	}
 
-	return TestApp{
-		App:     server.NewFiberApp(listener.Addr().String(), handlers.NewApplicantHandler(storage.NewApplicantStorage(connectionWithDB)), handlers.NewAdminHandler(storage.NewAdminStorage(connectionWithDB))),
-		Address: fmt.Sprintf("http://%s", listener.Addr().String()),
-		Conn:    connectionWithDB,
-	}, nil
+	return spawnAppWithRepositories(configuration, repositories)
 }
 
-func generateRandomDBName() string {
-	letterBytes := "abcdefghijklmnopqrstuvwxyz"
-	length := 36
-	result := make([]byte, length)
-	for i := 0; i < length; i++ {
-		result[i] = letterBytes[domain.GenerateRandomInt(int64(len(letterBytes)))]
+func SpawnSQLiteApp() (TestApp, error) {
+	return SpawnSQLiteAppWith(func(*config.Settings) {})
+}
+
+func SpawnSQLiteAppWith(configure func(*config.Settings)) (TestApp, error) {
+	configuration, err := loadConfiguration()
+
+	if err != nil {
+		return TestApp{}, err
 	}
 
-	return string(result)
-}
+	configure(&configuration)
 
-func configureDatabase(config config.DatabaseSettings) (*sqlx.DB, error) {
-	connectionWithoutDB := sqlx.MustConnect("postgres", config.WithoutDb())
+	dir, err := os.MkdirTemp("", "challengeserver")
 
-	_, err := connectionWithoutDB.Exec(fmt.Sprintf("CREATE DATABASE %s;", config.DatabaseName))
+	if err != nil {
+		return TestApp{}, err
+	}
+
+	configuration.Database.Driver = config.DriverSQLite
+	configuration.Database.Path = filepath.Join(dir, "challengeserver.db")
+
+	conn, err := db.OpenSQLiteConnection(configuration)
 
 	if err != nil {
-		return nil, err
+		return TestApp{}, err
 	}
 
-	connectionWithDB := sqlx.MustConnect("postgres", config.WithDb())
+	err = db.Migrate(configuration.Database)
+
+	if err != nil {
+		return TestApp{}, err
+	}
 
-	driver, err := postgres.WithInstance(connectionWithDB.DB, &postgres.Config{})
+	sqlite := storage.NewSQLiteStorage(conn, domain.DefaultChallengeRegistry(), domain.NewTokenHasher(configuration.Application.TokenHashKey))
+
+	app, err := spawnAppWithRepositories(configuration, storage.Repositories{
+		Applicants: sqlite,
+		Admin:      sqlite,
+		APIKeys:    sqlite,
+		Cohorts:    sqlite,
+	})
 
 	if err != nil {
-		return nil, err
+		return TestApp{}, err
 	}
 
+	app.Conn = conn
+
+	return app, nil
+}
+
+func loadConfiguration() (config.Settings, error) {
 	initialDir, err := os.Getwd()
 
 	if err != nil {
-		return nil, err
+		return config.Settings{}, err
 	}
 
 	err = os.Chdir("../")
 
 	if err != nil {
-		return nil, err
+		return config.Settings{}, err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations",
-		config.DatabaseName,
-		driver,
-	)
+	configuration, err := config.GetConfiguration()
 
 	if err != nil {
-		return nil, err
+		return config.Settings{}, err
 	}
 
 	err = os.Chdir(initialDir)
 
+	if err != nil {
+		return config.Settings{}, err
+	}
+
+	return configuration, nil
+}
+
+func spawnAppWithRepositories(configuration config.Settings, repositories storage.Repositories) (TestApp, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		return TestApp{}, err
+	}
+
+	_, adminKey, err := repositories.APIKeys.Create("test")
+
+	if err != nil {
+		return TestApp{}, err
+	}
+
+	outboxDir, err := os.MkdirTemp("", "challengeserver-outbox")
+
+	if err != nil {
+		return TestApp{}, err
+	}
+
+	outbox := filepath.Join(outboxDir, "outbox.jsonl")
+
+	return TestApp{
+		App:      server.NewFiberApp(listener.Addr().String(), configuration.Application, handlers.NewApplicantHandler(repositories.Applicants, repositories.Cohorts, mailer.NewOutboxMailer(outbox), configuration), handlers.NewAdminHandler(repositories.Admin, repositories.Cohorts, domain.DefaultChallengeRegistry(), configuration), handlers.NewAuthHandler(repositories.APIKeys, configuration), handlers.NewRateLimitHandler(ratelimit.NewMemoryStore(), configuration)),
+		Address:  fmt.Sprintf("http://%s", listener.Addr().String()),
+		AdminKey: adminKey,
+		Outbox:   outbox,
+		Tokens:   domain.NewTokenHasher(configuration.Application.TokenHashKey),
+	}, nil
+}
+
+func generateRandomDBName() string {
+	letterBytes := "abcdefghijklmnopqrstuvwxyz"
+	length := 36
+	result := make([]byte, length)
+	for i := 0; i < length; i++ {
+		result[i] = letterBytes[domain.GenerateRandomInt(int64(len(letterBytes)))]
+	}
+
+	return string(result)
+}
+
+func configureDatabase(config config.DatabaseSettings) (*sqlx.DB, error) {
+	connectionWithoutDB := sqlx.MustConnect("postgres", config.WithoutDb())
+
+	_, err := connectionWithoutDB.Exec(fmt.Sprintf("CREATE DATABASE %s;", config.DatabaseName))
+
 	if err != nil {
 		return nil, err
 	}
 
-	err = m.Up()
+	connectionWithDB := sqlx.MustConnect("postgres", config.WithDb())
+
+	err = db.Migrate(config)
 
 	if err != nil {
 		return nil, err
@@ -139,14 +239,22 @@ func configureDatabase(config config.DatabaseSettings) (*sqlx.DB, error) {
 	return connectionWithDB, nil
 }
 
+func AuthorizeAdmin(app TestApp, req *http.Request) *http.Request {
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", app.AdminKey))
+	return req
+}
+
+const SampleEmail = "garrett@example.com"
+
 func RegisterSampleApplicant(app TestApp) (*handlers.RegisterResponse, error) {
 	return RegisterSampleApplicantWithNUID(app, "002172052")
 }
 
 func RegisterRequest(app TestApp, nuid domain.NUID) (*http.Response, error) {
 	data := map[string]string{
-		"name": "Garrett",
-		"nuid": nuid.String(),
+		"name":  "Garrett",
+		"nuid":  nuid.String(),
+		"email": SampleEmail,
 	}
 
 	body, err := json.Marshal(data)
@@ -197,38 +305,51 @@ func GetChallengeFromBody(responseBody map[string]interface{}) ([]string, error)
 	return challengeStrings, nil
 }
 
-func GetTokenFromBody(responseBody map[string]interface{}) (*uuid.UUID, error) {
-	token, tokenExists := responseBody["token"]
-
-	if !tokenExists {
-		return nil, fmt.Errorf("response does not contain 'token' property")
+func GetChallengeFromResponse(resp *http.Response) ([]string, error) {
+	var responseBody map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+		return nil, err
 	}
 
-	parsedToken, err := uuid.Parse(token.(string))
+	return GetChallengeFromBody(responseBody)
+}
+
+func ReadOutboxMessage(app TestApp, to string) (*mailer.Message, error) {
+	messages, err := mailer.ReadOutbox(app.Outbox)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &parsedToken, nil
+	for _, message := range messages {
+		if message.To == to {
+			return &message, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no message to %s is in the outbox", to)
 }
-func GetChallengeFromResponse(resp *http.Response) ([]string, error) {
-	var responseBody map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+
+func GetTokenFromOutbox(app TestApp, to string) (*uuid.UUID, error) {
+	message, err := ReadOutboxMessage(app, to)
+
+	if err != nil {
 		return nil, err
 	}
 
-	return GetChallengeFromBody(responseBody)
-}
+	match := regexp.MustCompile(`token is ([0-9a-f-]{36})`).FindStringSubmatch(message.Body)
 
-func GetTokenFromResponse(resp *http.Response) (*uuid.UUID, error) {
-	var responseBody map[string]interface{}
+	if match == nil {
+		return nil, fmt.Errorf("message to %s does not contain a token", to)
+	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&responseBody); err != nil {
+	token, err := uuid.Parse(match[1])
+
+	if err != nil {
 		return nil, err
 	}
 
-	return GetTokenFromBody(responseBody)
+	return &token, nil
 }
 
 func SubmitSolution(app TestApp, registerResponse *handlers.RegisterResponse, solution []string) (*http.Response, error) {
@@ -264,13 +385,7 @@ func SubmitCorrectSolution(app TestApp) (*http.Response, error) {
 	return SubmitCorrectSolutionWithNUID(app, *nuid)
 }
 
-func SubmitCorrectSolutionWithNUID(app TestApp, nuid domain.NUID) (*http.Response, error) {
-	registerResp, err := RegisterSampleApplicantWithNUID(app, nuid)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to register applicant: %v", err)
-	}
-
+func CorrectSolution(registerResp *handlers.RegisterResponse) ([]string, error) {
 	solution := []string{}
 
 	for _, challenge := range registerResp.Challenge {
@@ -284,6 +399,22 @@ func SubmitCorrectSolutionWithNUID(app TestApp, nuid domain.NUID) (*http.Respons
 		}
 	}
 
+	return solution, nil
+}
+
+func SubmitCorrectSolutionWithNUID(app TestApp, nuid domain.NUID) (*http.Response, error) {
+	registerResp, err := RegisterSampleApplicantWithNUID(app, nuid)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to register applicant: %v", err)
+	}
+
+	solution, err := CorrectSolution(registerResp)
+
+	if err != nil {
+		return nil, err
+	}
+
 	submitResp, err := SubmitSolution(app, registerResp, solution)
 
 	if err != nil {
@@ -296,3 +427,17 @@ func SubmitCorrectSolutionWithNUID(app TestApp, nuid domain.NUID) (*http.Respons
 
 	return submitResp, nil
 }
+
+func GetProblemFromResponse(resp *http.Response) (*handlers.Problem, error) {
+	if contentType := resp.Header.Get("Content-Type"); contentType != handlers.MIMEApplicationProblemJSON {
+		return nil, fmt.Errorf("unexpected content type %s", contentType)
+	}
+
+	var problem handlers.Problem
+
+	if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+		return nil, err
+	}
+
+	return &problem, nil
+}
@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStorage_RegisterRejectsDuplicateNUID(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	resp, err := RegisterRequest(app, "002172052")
+
+	assert.Nil(err)
+
+	assert.Equal(409, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeAlreadyRegistered, problem.Code)
+}
+
+func TestMemoryStorage_ChallengeAndForgotToken(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	challenge, err := GetChallengeFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(registerResp.Challenge, challenge)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/002172052", app.Address), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(202, resp.StatusCode)
+
+	token, err := GetTokenFromOutbox(app, SampleEmail)
+
+	assert.Nil(err)
+
+	assert.NotEqual(registerResp.Token, *token)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(404, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeTokenNotFound, problem.Code)
+}
+
+func TestMemoryStorage_SubmitIsReflectedInAdminViews(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	_, err = SubmitCorrectSolutionWithNUID(app, "000000001")
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicantWithNUID(app, "000000002")
+
+	assert.Nil(err)
+
+	resp, err := SubmitSolution(app, registerResp, []string{"wrong"})
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	correct, status, err := getApplicants(app, "correct=true")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(1, len(correct.Applicants))
+	assert.Equal(domain.NUID("000000001"), correct.Applicants[0].NUID)
+
+	firstPage, status, err := getApplicants(app, "limit=1")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(1, len(firstPage.Applicants))
+	assert.NotEmpty(firstPage.NextCursor)
+
+	secondPage, status, err := getApplicants(app, fmt.Sprintf("limit=1&cursor=%s", firstPage.NextCursor))
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(1, len(secondPage.Applicants))
+	assert.NotEqual(firstPage.Applicants[0].NUID, secondPage.Applicants[0].NUID)
+	assert.Empty(secondPage.NextCursor)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/stats", app.Address), nil))
+
+	resp, err = app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	var stats handlers.StatsResponse
+
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&stats))
+
+	assert.Equal(int64(2), stats.Registrations)
+	assert.Equal(int64(2), stats.Submissions)
+	assert.Equal(int64(1), stats.CorrectApplicants)
+
+	req = AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/submission/2", app.Address), nil))
+
+	resp, err = app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	req = AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/submission/3", app.Address), nil))
+
+	resp, err = app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(404, resp.StatusCode)
+}
+
+func TestMemoryStorage_RegisterRequiresAValidEmail(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	body, err := json.Marshal(map[string]string{"name": "Garrett", "nuid": "002172052", "email": "garrett"})
+
+	assert.Nil(err)
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("%s/register", app.Address), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(400, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeEmailInvalid, problem.Code)
+}
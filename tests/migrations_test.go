@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/db"
+	"github.com/garrettladley/generate_coding_challenge_server_go/migrations"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func migrationVersions(t *testing.T, fsys fs.FS, dir string) map[string][]string {
+	entries, err := fs.ReadDir(fsys, dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions := make(map[string][]string)
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		parts := strings.SplitN(name, ".", 2)
+		versions[parts[0]] = append(versions[parts[0]], parts[1])
+	}
+
+	return versions
+}
+
+func TestMigrations_EveryUpHasADownInBothDialects(t *testing.T) {
+	assert := assert.New(t)
+
+	postgresVersions := migrationVersions(t, migrations.Postgres, ".")
+	sqliteVersions := migrationVersions(t, migrations.SQLite, "sqlite")
+
+	assert.NotEmpty(postgresVersions)
+
+	for _, versions := range []map[string][]string{postgresVersions, sqliteVersions} {
+		for version, directions := range versions {
+			sort.Strings(directions)
+			assert.Equal([]string{"down.sql", "up.sql"}, directions, version)
+		}
+	}
+
+	assert.Equal(len(postgresVersions), len(sqliteVersions))
+
+	for version := range postgresVersions {
+		_, exists := sqliteVersions[version]
+		assert.True(exists, version)
+	}
+}
+
+func TestMigrations_SQLiteMigratesDownAndBackUp(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := config.DatabaseSettings{
+		Driver: config.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "challengeserver.db"),
+	}
+
+	assert.Nil(db.Migrate(settings))
+
+	m, err := db.NewMigrate(settings)
+
+	assert.Nil(err)
+
+	latest, dirty, err := m.Version()
+
+	assert.Nil(err)
+	assert.False(dirty)
+
+	assert.Nil(m.Down())
+
+	assert.Nil(m.Up())
+
+	version, _, err := m.Version()
+
+	assert.Nil(err)
+	assert.Equal(latest, version)
+
+	srcErr, dbErr := m.Close()
+
+	assert.Nil(srcErr)
+	assert.Nil(dbErr)
+
+	assert.Nil(db.Migrate(settings))
+}
+
+func TestMigrations_ConcurrentPostgresInstancesMigrateOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	initialDir, err := os.Getwd()
+
+	assert.Nil(err)
+
+	assert.Nil(os.Chdir("../"))
+
+	configuration, err := config.GetConfiguration()
+
+	assert.Nil(os.Chdir(initialDir))
+	assert.Nil(err)
+
+	configuration.Database.DatabaseName = generateRandomDBName()
+
+	connectionWithoutDB := sqlx.MustConnect("postgres", configuration.Database.WithoutDb())
+
+	_, err = connectionWithoutDB.Exec(fmt.Sprintf("CREATE DATABASE %s;", configuration.Database.DatabaseName))
+
+	assert.Nil(err)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+
+	for i := range errs {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = db.Migrate(configuration.Database)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.Nil(err)
+	}
+
+	connectionWithDB := sqlx.MustConnect("postgres", configuration.Database.WithDb())
+
+	var count int
+
+	assert.Nil(connectionWithDB.Get(&count, "SELECT COUNT(*) FROM applicants;"))
+}
+
+func TestMigrations_SQLiteRefusesToDropHashedTokens(t *testing.T) {
+	assert := assert.New(t)
+	settings := sqliteCLISettings(t)
+
+	_, err := executeCLI(settings, "migrate", "up")
+
+	assert.Nil(err)
+
+	_, err = executeCLI(settings, "seed", "-count", "1")
+
+	assert.Nil(err)
+
+	m, err := db.NewMigrate(settings.Database)
+
+	assert.Nil(err)
+
+	defer m.Close()
+
+	err = m.Migrate(20231113120000)
+
+	assert.NotNil(err)
+	assert.Contains(fmt.Sprint(err), "applicants_with_hashed_tokens")
+}
@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+var fiberParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+func openAPIPath(path string) string {
+	return fiberParam.ReplaceAllString(path, "{$1}")
+}
+
+func fetchOpenAPIDocument(app TestApp) (*openAPIDocument, error) {
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/openapi.json", app.Address), nil))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var document openAPIDocument
+
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+func (d *openAPIDocument) documents(method string, path string) bool {
+	operations, ok := d.Paths[path]
+
+	if !ok {
+		return false
+	}
+
+	_, ok = operations[strings.ToLower(method)]
+
+	return ok
+}
+
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func TestOpenAPI_DocumentsEveryRegisteredRoute(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	document, err := fetchOpenAPIDocument(app)
+
+	assert.Nil(err)
+	assert.Equal("3.0.3", document.OpenAPI)
+
+	registered := make(map[string]bool)
+
+	for _, route := range app.App.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+
+		path := openAPIPath(route.Path)
+		registered[route.Method+" "+path] = true
+
+		assert.True(document.documents(route.Method, path), "%s %s is registered but missing from openapi.json", route.Method, route.Path)
+	}
+
+	for path, operations := range document.Paths {
+		for method := range operations {
+			assert.True(registered[strings.ToUpper(method)+" "+path], "%s %s is in openapi.json but not registered", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestOpenAPI_MarksLegacyRoutesDeprecated(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	document, err := fetchOpenAPIDocument(app)
+
+	assert.Nil(err)
+
+	for path, operations := range document.Paths {
+		_, versioned := document.Paths["/v1"+path]
+
+		for method, raw := range operations {
+			var operation struct {
+				Deprecated bool `json:"deprecated"`
+			}
+
+			assert.Nil(json.Unmarshal(raw, &operation))
+			assert.Equal(versioned, operation.Deprecated, "%s %s has the wrong deprecated flag", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestOpenAPI_SchemasMatchResponseTypes(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	document, err := fetchOpenAPIDocument(app)
+
+	assert.Nil(err)
+
+	types := map[string]interface{}{
+		"Problem":                   handlers.Problem{},
+		"TimeToCompletion":          handlers.TimeToCompletion{},
+		"RegisterRequestBody":       handlers.RegisterRequestBody{},
+		"RegisterResponse":          storage.RegisterResult{},
+		"ForgotTokenResponse":       handlers.ForgotTokenResponse{},
+		"ChallengeResponse":         handlers.ChallengeResponse{},
+		"SubmitResponseBody":        handlers.SubmitResponseBody{},
+		"ApplicantResponse":         handlers.ApplicantResponse{},
+		"ApplicantsResponse":        handlers.ApplicantsResponse{},
+		"ApplicantListItem":         handlers.ApplicantListItem{},
+		"SubmissionResponse":        handlers.SubmissionResponse{},
+		"SubmissionDiffEntry":       handlers.SubmissionDiffEntry{},
+		"ActivityResponse":          handlers.ActivityResponse{},
+		"StatsResponse":             handlers.StatsResponse{},
+		"AttemptsBucket":            handlers.AttemptsBucket{},
+		"TimeToCompletionStats":     handlers.TimeToCompletionStats{},
+		"CompletionHistogramBucket": handlers.CompletionHistogramBucket{},
+		"LeaderboardEntry":          handlers.LeaderboardEntry{},
+		"RotateTokenResponse":       handlers.RotateTokenResponse{},
+		"TokenHistoryEntry":         handlers.TokenHistoryEntry{},
+		"CohortSettings":            storage.CohortSettings{},
+		"CohortResponse":            handlers.CohortResponse{},
+		"CreateCohortRequestBody":   handlers.CreateCohortRequestBody{},
+		"CohortComparison":          handlers.CohortComparison{},
+	}
+
+	for name, value := range types {
+		schema, ok := document.Components.Schemas[name]
+
+		if !assert.True(ok, "schema %s is missing from openapi.json", name) {
+			continue
+		}
+
+		properties := make([]string, 0, len(schema.Properties))
+
+		for property := range schema.Properties {
+			properties = append(properties, property)
+		}
+
+		assert.ElementsMatch(jsonFieldNames(reflect.TypeOf(value)), properties, "schema %s does not match its Go type", name)
+	}
+}
+
+func TestOpenAPI_ServesDocsPage(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/docs", app.Address), nil))
+
+	assert.Nil(err)
+	assert.Equal(200, resp.StatusCode)
+	assert.Contains(resp.Header.Get("Content-Type"), "text/html")
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler_ReturnsProblemForUnknownRoute(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("%s/foo", app.Address), nil)
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(404, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeRouteNotFound, problem.Code)
+	assert.Equal(404, problem.Status)
+	assert.Equal("Not Found", problem.Title)
+	assert.Equal("/foo", problem.Instance)
+}
+
+func TestErrorHandler_DoesNotLeakUnexpectedErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handlers.ErrorHandler,
+	})
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		return errors.New("pq: relation \"applicants\" does not exist")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+
+	assert.Nil(err)
+
+	assert.Equal(500, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+
+	assert.Nil(err)
+
+	assert.NotContains(string(body), "pq:")
+	assert.Contains(string(body), string(handlers.CodeInternalError))
+	assert.Equal(handlers.MIMEApplicationProblemJSON, resp.Header.Get("Content-Type"))
+}
@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func spawnRateLimitedApp(route string, limits config.RouteRateLimitSettings) (TestApp, error) {
+	return SpawnMemoryAppWith(func(settings *config.Settings) {
+		settings.Application.RateLimit.Routes = map[string]config.RouteRateLimitSettings{route: limits}
+	})
+}
+
+func forgotTokenRequest(app TestApp, nuid string) (int, string, error) {
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/%s", app.Address, nuid), nil))
+
+	if err != nil {
+		return 0, "", err
+	}
+
+	return resp.StatusCode, resp.Header.Get("Retry-After"), nil
+}
+
+func TestRateLimit_ForgotTokenIsLimitedPerNUID(t *testing.T) {
+	assert := assert.New(t)
+	app, err := spawnRateLimitedApp("forgot_token", config.RouteRateLimitSettings{
+		PerKey: config.RateLimit{Requests: 2, Per: time.Minute},
+	})
+
+	assert.Nil(err)
+
+	for i := 0; i < 2; i++ {
+		status, _, err := forgotTokenRequest(app, "002172052")
+
+		assert.Nil(err)
+		assert.Equal(202, status)
+	}
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/002172052", app.Address), nil))
+
+	assert.Nil(err)
+	assert.Equal(429, resp.StatusCode)
+
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+
+	assert.Nil(err)
+	assert.True(retryAfter > 0 && retryAfter <= 30)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+	assert.Equal(handlers.CodeRateLimited, problem.Code)
+
+	status, _, err := forgotTokenRequest(app, "002172053")
+
+	assert.Nil(err)
+	assert.Equal(202, status)
+}
+
+func TestRateLimit_RegisterIsLimitedPerIP(t *testing.T) {
+	assert := assert.New(t)
+	app, err := spawnRateLimitedApp("register", config.RouteRateLimitSettings{
+		PerIP: config.RateLimit{Requests: 2, Per: time.Hour},
+	})
+
+	assert.Nil(err)
+
+	for _, nuid := range []string{"000000001", "000000002"} {
+		resp, err := RegisterRequest(app, domain.NUID(nuid))
+
+		assert.Nil(err)
+		assert.Equal(200, resp.StatusCode)
+	}
+
+	resp, err := RegisterRequest(app, domain.NUID("000000003"))
+
+	assert.Nil(err)
+	assert.Equal(429, resp.StatusCode)
+	assert.NotEmpty(resp.Header.Get("Retry-After"))
+}
+
+func TestRateLimit_SharesBucketsAcrossLegacyAndVersionedRoutes(t *testing.T) {
+	assert := assert.New(t)
+	app, err := spawnRateLimitedApp("forgot_token", config.RouteRateLimitSettings{
+		PerKey: config.RateLimit{Requests: 1, Per: time.Minute},
+	})
+
+	assert.Nil(err)
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/v1/forgot_token/002172052", app.Address), nil))
+
+	assert.Nil(err)
+	assert.Equal(202, resp.StatusCode)
+
+	status, _, err := forgotTokenRequest(app, "002172052")
+
+	assert.Nil(err)
+	assert.Equal(429, status)
+}
+
+func TestMemoryStore_RefillsTokensOverTime(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2023, 11, 6, 12, 0, 0, 0, time.UTC)
+	store := ratelimit.NewMemoryStore()
+	store.Now = func() time.Time { return now }
+
+	limit := ratelimit.Limit{Requests: 6, Per: time.Minute, Burst: 2}
+
+	for _, remaining := range []int{1, 0} {
+		result, err := store.Take("key", limit)
+
+		assert.Nil(err)
+		assert.True(result.Allowed)
+		assert.Equal(remaining, result.Remaining)
+	}
+
+	result, err := store.Take("key", limit)
+
+	assert.Nil(err)
+	assert.False(result.Allowed)
+	assert.Equal(10*time.Second, result.RetryAfter)
+
+	now = now.Add(5 * time.Second)
+
+	result, err = store.Take("key", limit)
+
+	assert.Nil(err)
+	assert.False(result.Allowed)
+	assert.Equal(5*time.Second, result.RetryAfter)
+
+	now = now.Add(5 * time.Second)
+
+	result, err = store.Take("key", limit)
+
+	assert.Nil(err)
+	assert.True(result.Allowed)
+
+	result, err = store.Take("other", limit)
+
+	assert.Nil(err)
+	assert.True(result.Allowed)
+}
+
+func TestRateLimit_UsesTheConfiguredIPHeader(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryAppWith(func(settings *config.Settings) {
+		settings.Application.RateLimit.IPHeader = "do-connecting-ip"
+		settings.Application.RateLimit.Routes = map[string]config.RouteRateLimitSettings{
+			"forgot_token": {PerIP: config.RateLimit{Requests: 1, Per: time.Minute}},
+		}
+	})
+
+	assert.Nil(err)
+
+	for _, test := range []struct {
+		ip     string
+		status int
+	}{
+		{"203.0.113.1", 202},
+		{"203.0.113.2", 202},
+		{"203.0.113.1", 429},
+	} {
+		req := httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/002172052", app.Address), nil)
+		req.Header.Set("do-connecting-ip", test.ip)
+
+		resp, err := app.App.Test(req)
+
+		assert.Nil(err)
+		assert.Equal(test.status, resp.StatusCode, test.ip)
+	}
+}
+
+type recordingStore struct {
+	keys []string
+}
+
+func (r *recordingStore) Take(key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	r.keys = append(r.keys, key)
+
+	return ratelimit.Result{Allowed: true}, nil
+}
+
+func TestRateLimit_SubmitIsKeyedByTheTokenHash(t *testing.T) {
+	assert := assert.New(t)
+
+	configuration, err := loadConfiguration()
+
+	assert.Nil(err)
+
+	configuration.Application.RateLimit.Routes = map[string]config.RouteRateLimitSettings{
+		"submit": {PerKey: config.RateLimit{Requests: 1, Per: time.Minute}},
+	}
+
+	store := &recordingStore{}
+	rateLimitHandlers := handlers.NewRateLimitHandler(store, configuration)
+
+	app := fiber.New()
+	app.Post("/submit/:token", rateLimitHandlers.Limit("submit", rateLimitHandlers.TokenKey("token")), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	token := uuid.New().String()
+
+	resp, err := app.Test(httptest.NewRequest("POST", fmt.Sprintf("/submit/%s", token), nil))
+
+	assert.Nil(err)
+	assert.Equal(200, resp.StatusCode)
+
+	tokens := domain.NewTokenHasher(configuration.Application.TokenHashKey)
+
+	assert.Equal([]string{fmt.Sprintf("submit:key:%s", tokens.Hash(token))}, store.keys)
+}
+
+func TestPostgresStore_PrunesFullBuckets(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	_, err = app.Conn.Exec("INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at, full_at) VALUES ('stale', 1, now() - interval '1 hour', now() - interval '1 minute'), ('draining', 0, now(), now() + interval '1 hour');")
+
+	assert.Nil(err)
+
+	store := ratelimit.NewPostgresStore(app.Conn)
+
+	result, err := store.Take("fresh", ratelimit.Limit{Requests: 1, Per: time.Minute})
+
+	assert.Nil(err)
+	assert.True(result.Allowed)
+
+	var keys []string
+
+	assert.Nil(app.Conn.Select(&keys, "SELECT bucket_key FROM rate_limit_buckets ORDER BY bucket_key;"))
+	assert.Equal([]string{"draining", "fresh"}, keys)
+}
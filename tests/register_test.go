@@ -9,15 +9,15 @@ import (
 	"testing"
 
 	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
 	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
-	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 type RegisterDB struct {
 	ApplicantName sql.NullString      `db:"applicant_name"`
 	NUID          sql.NullString      `db:"nuid"`
-	Token         sql.NullString      `db:"token"`
+	TokenHash     sql.NullString      `db:"token_hash"`
 	Challenge     storage.StringArray `db:"challenge"`
 }
 
@@ -38,13 +38,13 @@ func TestRegister_ReturnsA200ForValidRequestBody(t *testing.T) {
 
 	var dbResult RegisterDB
 
-	err = app.Conn.Get(&dbResult, "SELECT applicant_name, nuid, token, challenge FROM applicants;")
+	err = app.Conn.Get(&dbResult, "SELECT applicant_name, nuid, token_hash, challenge FROM applicants;")
 
 	assert.Nil(err)
 
 	assert.True(dbResult.ApplicantName.Valid)
 	assert.True(dbResult.NUID.Valid)
-	assert.True(dbResult.Token.Valid)
+	assert.True(dbResult.TokenHash.Valid)
 	assert.True(len(dbResult.Challenge) > 0)
 
 	name, err := domain.ParseApplicantName(dbResult.ApplicantName.String)
@@ -55,15 +55,13 @@ func TestRegister_ReturnsA200ForValidRequestBody(t *testing.T) {
 
 	assert.Nil(err)
 
-	token, err := uuid.Parse(dbResult.Token.String)
-
-	assert.Nil(err)
-
 	assert.Equal("Garrett", name.String())
 
 	assert.Equal("002172052", nuid.String())
 
-	assert.Equal(token, resp.Token)
+	assert.Equal(app.Tokens.Hash(resp.Token.String()), dbResult.TokenHash.String)
+
+	assert.NotContains(dbResult.TokenHash.String, resp.Token.String())
 
 	assert.Equal(len(resp.Challenge), len(dbResult.Challenge))
 
@@ -78,18 +76,25 @@ func TestRegister_ReturnsA400WhenRequestBodyPropertiesAreMissing(t *testing.T) {
 
 	assert.Nil(err)
 
-	testCases := make([]map[string]string, 3)
+	testCases := make([]map[string]string, 4)
 
 	testCases[0] = map[string]string{
-		"name": "Garrett",
+		"name":  "Garrett",
+		"email": SampleEmail,
 	}
 
 	testCases[1] = map[string]string{
-		"nuid": "002172052",
+		"nuid":  "002172052",
+		"email": SampleEmail,
 	}
 
 	testCases[2] = map[string]string{}
 
+	testCases[3] = map[string]string{
+		"name": "Garrett",
+		"nuid": "002172052",
+	}
+
 	for _, testCase := range testCases {
 		body, err := json.Marshal(testCase)
 
@@ -124,19 +129,27 @@ func TestRegister_ReturnsA400WhenRequestBodyPropertiesArePresentButInvalid(t *te
 
 	assert.Nil(err)
 
-	testCases := make([]map[string]string, 3)
+	testCases := make([]map[string]string, 4)
 
 	testCases[0] = map[string]string{
-		"name": "",
-		"nuid": "002172052",
+		"name":  "",
+		"nuid":  "002172052",
+		"email": SampleEmail,
 	}
 
 	testCases[1] = map[string]string{
-		"name": "Garrett",
-		"nuid": "",
+		"name":  "Garrett",
+		"nuid":  "",
+		"email": SampleEmail,
 	}
 
-	testCases[2] = map[string]string{"name": "", "nuid": ""}
+	testCases[2] = map[string]string{"name": "", "nuid": "", "email": ""}
+
+	testCases[3] = map[string]string{
+		"name":  "Garrett",
+		"nuid":  "002172052",
+		"email": "Garrett <garrett@example.com>",
+	}
 
 	for _, testCase := range testCases {
 		body, err := json.Marshal(testCase)
@@ -186,6 +199,12 @@ func TestRegister_ReturnsA409ForUserThatAlreadyExists(t *testing.T) {
 
 	assert.Equal(409, resp.StatusCode)
 
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeAlreadyRegistered, problem.Code)
+
 	var count int
 
 	err = app.Conn.Get(&count, "SELECT COUNT(*) FROM applicants WHERE nuid = $1;", nuid.String())
@@ -194,3 +213,56 @@ func TestRegister_ReturnsA409ForUserThatAlreadyExists(t *testing.T) {
 
 	assert.Equal(1, count)
 }
+
+func TestRegister_RecordsChallengeTypeAndVersion(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	var dbResult struct {
+		ChallengeType    string `db:"challenge_type"`
+		ChallengeVersion int    `db:"challenge_version"`
+	}
+
+	err = app.Conn.Get(&dbResult, "SELECT challenge_type, challenge_version FROM applicants;")
+
+	assert.Nil(err)
+
+	assert.Equal(domain.ColorOneEditAwayName, dbResult.ChallengeType)
+	assert.Equal(1, dbResult.ChallengeVersion)
+}
+
+func TestRegister_ChallengeCanBeRegeneratedFromSeed(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	var dbResult struct {
+		ChallengeType    string              `db:"challenge_type"`
+		ChallengeVersion int                 `db:"challenge_version"`
+		Seed             int64               `db:"seed"`
+		Challenge        storage.StringArray `db:"challenge"`
+		Solution         storage.StringArray `db:"solution"`
+	}
+
+	err = app.Conn.Get(&dbResult, "SELECT challenge_type, challenge_version, seed, challenge, solution FROM applicants;")
+
+	assert.Nil(err)
+
+	regenerated, err := domain.DefaultChallengeRegistry().Regenerate(dbResult.ChallengeType, dbResult.ChallengeVersion, dbResult.Seed)
+
+	assert.Nil(err)
+
+	assert.Equal([]string(dbResult.Challenge), regenerated.Challenge)
+	assert.Equal([]string(dbResult.Solution), regenerated.Solution)
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteStorage_RegisterRejectsDuplicateNUID(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnSQLiteApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	resp, err := RegisterRequest(app, "002172052")
+
+	assert.Nil(err)
+
+	assert.Equal(409, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeAlreadyRegistered, problem.Code)
+}
+
+func TestSQLiteStorage_EnforcesDomainChecks(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnSQLiteApp()
+
+	assert.Nil(err)
+
+	insertStatement := "INSERT INTO applicants (cohort, nuid, applicant_name, registration_time, token_hash, challenge, solution) VALUES ('default', ?, ?, ?, ?, ?, ?);"
+
+	_, err = app.Conn.Exec(insertStatement, "00217205a", "Garrett", time.Now(), "token-1", "[]", "[]")
+
+	assert.NotNil(err)
+
+	_, err = app.Conn.Exec(insertStatement, "002172052", "Garrett {Ladley}", time.Now(), "token-2", "[]", "[]")
+
+	assert.NotNil(err)
+
+	_, err = app.Conn.Exec(insertStatement, "002172052", "Garrett", time.Now(), "token-3", "not an array", "[]")
+
+	assert.NotNil(err)
+
+	_, err = app.Conn.Exec(insertStatement, "002172052", "Garrett", time.Now(), "token-4", "[]", "[]")
+
+	assert.Nil(err)
+
+	_, err = app.Conn.Exec("UPDATE applicants SET email = ? WHERE nuid = ?;", "not an email", "002172052")
+
+	assert.NotNil(err)
+}
+
+func TestSQLiteStorage_ForgotTokenEmailsANewTokenToTheRegisteredAddress(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnSQLiteApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/002172052", app.Address), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(202, resp.StatusCode)
+
+	token, err := GetTokenFromOutbox(app, SampleEmail)
+
+	assert.Nil(err)
+
+	assert.NotEqual(registerResp.Token, *token)
+
+	var pendingTokenHash string
+
+	assert.Nil(app.Conn.Get(&pendingTokenHash, "SELECT pending_token_hash FROM applicants WHERE nuid = ?;", "002172052"))
+
+	assert.Equal(app.Tokens.Hash(token.String()), pendingTokenHash)
+}
+
+func TestSQLiteStorage_RoundTripsChallengeAndSubmissions(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnSQLiteApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicantWithNUID(app, "000000001")
+
+	assert.Nil(err)
+
+	resp, err := app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	challenge, err := GetChallengeFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(registerResp.Challenge, challenge)
+
+	resp, err = SubmitSolution(app, registerResp, []string{"wrong, with a comma", ""})
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	_, err = SubmitCorrectSolutionWithNUID(app, "000000002")
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicantWithNUID(app, "000000003")
+
+	assert.Nil(err)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/000000001/submissions", app.Address), nil))
+
+	resp, err = app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	var submissions []handlers.SubmissionResponse
+
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&submissions))
+
+	assert.Equal(1, len(submissions))
+	assert.Equal([]string{"wrong, with a comma", ""}, submissions[0].Submission)
+
+	byCompletion, status, err := getApplicants(app, "sort=time_to_completion")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(3, len(byCompletion.Applicants))
+	assert.Equal(domain.NUID("000000003"), byCompletion.Applicants[2].NUID)
+
+	firstPage, status, err := getApplicants(app, "limit=1")
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(domain.NUID("000000001"), firstPage.Applicants[0].NUID)
+
+	secondPage, status, err := getApplicants(app, fmt.Sprintf("limit=1&cursor=%s", firstPage.NextCursor))
+
+	assert.Nil(err)
+	assert.Equal(200, status)
+	assert.Equal(domain.NUID("000000002"), secondPage.Applicants[0].NUID)
+
+	req = AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/stats", app.Address), nil))
+
+	resp, err = app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	var stats handlers.StatsResponse
+
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&stats))
+
+	assert.Equal(int64(3), stats.Registrations)
+	assert.Equal(int64(2), stats.Submissions)
+	assert.Equal(int64(2), stats.SubmittedApplicants)
+	assert.Equal(int64(1), stats.CorrectApplicants)
+}
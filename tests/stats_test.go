@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats_ReturnsA200WithCohortStatistics(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicantWithNUID(app, "000000001")
+
+	assert.Nil(err)
+
+	_, err = SubmitCorrectSolutionWithNUID(app, "000000002")
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicantWithNUID(app, "000000003")
+
+	assert.Nil(err)
+
+	for i := 0; i < 2; i++ {
+		_, err = SubmitSolution(app, registerResp, []string{})
+
+		assert.Nil(err)
+	}
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/stats", app.Address), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	var statsResponseBody handlers.StatsResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&statsResponseBody)
+
+	assert.Nil(err)
+
+	assert.Equal(int64(3), statsResponseBody.Registrations)
+	assert.Equal(int64(3), statsResponseBody.Submissions)
+	assert.Equal(int64(2), statsResponseBody.SubmittedApplicants)
+	assert.Equal(int64(1), statsResponseBody.CorrectApplicants)
+	assert.Equal(0.5, statsResponseBody.PassRate)
+	assert.Equal([]handlers.AttemptsBucket{
+		{Attempts: 0, Applicants: 1},
+		{Attempts: 1, Applicants: 1},
+		{Attempts: 2, Applicants: 1},
+	}, statsResponseBody.AttemptsPerApplicant)
+	assert.Equal(1, len(statsResponseBody.Leaderboard))
+	assert.Equal(domain.NUID("000000002"), statsResponseBody.Leaderboard[0].NUID)
+	assert.Equal(1, statsResponseBody.Leaderboard[0].Rank)
+	assert.Contains(statsResponseBody.TimeToCompletion.Percentiles, "p50")
+	assert.Equal(1, statsResponseBody.TimeToCompletion.Histogram[0].Count)
+}
+
+func TestPercentile_InterpolatesBetweenRanks(t *testing.T) {
+	assert := assert.New(t)
+
+	durations := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second}
+
+	assert.Equal(time.Second, domain.Percentile(durations, 0))
+	assert.Equal(2500*time.Millisecond, domain.Percentile(durations, 50))
+	assert.Equal(4*time.Second, domain.Percentile(durations, 100))
+	assert.Equal(time.Duration(0), domain.Percentile([]time.Duration{}, 50))
+}
+
+func TestHistogram_CountsDurationsIntoBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	buckets := domain.Histogram([]time.Duration{time.Minute, 2 * time.Hour, 48 * time.Hour}, []time.Duration{time.Hour, 24 * time.Hour})
+
+	assert.Equal(3, len(buckets))
+	assert.Equal(1, buckets[0].Count)
+	assert.Equal(1, buckets[1].Count)
+	assert.Equal(1, buckets[2].Count)
+}
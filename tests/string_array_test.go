@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringArray_ScansPostgresArrayLiterals(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := map[string][]string{
+		`{}`:                               {},
+		`{""}`:                             {""},
+		`{a,b}`:                            {"a", "b"},
+		`{"a,b",c}`:                        {"a,b", "c"},
+		`{"say \"hi\"","back\\slash"}`:     {`say "hi"`, `back\slash`},
+		`{"{brace}","NULL"," padded "}`:    {"{brace}", "NULL", " padded "},
+		`{"",blue,"",red}`:                 {"", "blue", "", "red"},
+		`{"\\\"","\\\\"}`:                  {`\"`, `\\`},
+		`{héllo,"wörld, again"}`:           {"héllo", "wörld, again"},
+		`{"line` + "\n" + `break","tab	"}`: {"line\nbreak", "tab\t"},
+	}
+
+	for literal, expected := range cases {
+		var array storage.StringArray
+
+		assert.Nil(array.Scan([]byte(literal)), literal)
+		assert.Equal(expected, []string(array), literal)
+	}
+}
+
+func TestStringArray_RejectsMalformedAndNullElements(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, literal := range []string{
+		``,
+		`a,b`,
+		`{a,b`,
+		`{"a}`,
+		`{a,NULL}`,
+		`{{a},{b}}`,
+		`{"a"b}`,
+		`[1:1]={a}`,
+	} {
+		var array storage.StringArray
+
+		assert.NotNil(array.Scan([]byte(literal)), literal)
+	}
+}
+
+func roundTripStringArray(strs []string) ([]string, error) {
+	value, err := storage.StringArray(strs).Value()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var array storage.StringArray
+
+	if err := array.Scan([]byte(value.(string))); err != nil {
+		return nil, err
+	}
+
+	return array, nil
+}
+
+func addStringArraySeeds(f *testing.F) {
+	f.Add("", "")
+	f.Add("a,b", `"quoted"`)
+	f.Add(`back\slash`, "{brace}")
+	f.Add("NULL", "null")
+	f.Add(" ", "\t\n")
+	f.Add(`\"`, `"\`)
+	f.Add("héllo", "wörld")
+}
+
+func FuzzStringArray_RoundTrip(f *testing.F) {
+	addStringArraySeeds(f)
+
+	f.Fuzz(func(t *testing.T, a string, b string) {
+		strs := []string{a, b, a + "," + b}
+
+		roundTripped, err := roundTripStringArray(strs)
+
+		if err != nil {
+			t.Fatalf("failed to round trip %q: %v", strs, err)
+		}
+
+		if len(roundTripped) != len(strs) {
+			t.Fatalf("expected %q but got %q", strs, roundTripped)
+		}
+
+		for i := range strs {
+			if strs[i] != roundTripped[i] {
+				t.Fatalf("expected %q but got %q", strs, roundTripped)
+			}
+		}
+	})
+}
+
+func FuzzStringArray_ChallengeSurvivesPostgres(f *testing.F) {
+	addStringArraySeeds(f)
+
+	app, err := SpawnApp()
+
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	challenges := domain.DefaultChallengeRegistry()
+	applicantStorage := storage.NewApplicantStorage(app.Conn, challenges, app.Tokens)
+
+	f.Fuzz(func(t *testing.T, a string, b string) {
+		if !utf8.ValidString(a+b) || strings.ContainsRune(a+b, 0) {
+			t.Skip("postgres text cannot store invalid UTF-8 or NUL bytes")
+		}
+
+		challenge := []string{a, b}
+		solution := []string{b, a, a + "," + b}
+
+		_, err := app.Conn.Exec("UPDATE applicants SET challenge=$1, solution=$2 WHERE token_hash=$3;", storage.StringArray(challenge), storage.StringArray(solution), app.Tokens.Hash(registerResp.Token.String()))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		challengeResult, err := applicantStorage.Challenge(registerResp.Token, storage.ChallengeFetch{})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, challenge, []string(challengeResult.Challenge))
+
+		submitResult, err := applicantStorage.Submit(registerResp.Token, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, solution, []string(submitResult.Solution))
+	})
+}
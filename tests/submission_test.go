@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/domain"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmission_ReturnsA200WithDiffAgainstSolution(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	nuid, err := domain.ParseNUID("002172052")
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicantWithNUID(app, *nuid)
+
+	assert.Nil(err)
+
+	submitted := []string{"foo", "bar"}
+
+	submitResp, err := SubmitSolution(app, registerResp, submitted)
+
+	assert.Nil(err)
+
+	assert.Equal(200, submitResp.StatusCode)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s/submissions", app.Address, nuid), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	var submissionsResponseBody []handlers.SubmissionResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&submissionsResponseBody)
+
+	assert.Nil(err)
+
+	assert.Equal(1, len(submissionsResponseBody))
+
+	req = AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/submission/%d", app.Address, submissionsResponseBody[0].SubmissionID), nil))
+
+	resp, err = app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	var submissionResponseBody handlers.SubmissionResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&submissionResponseBody)
+
+	assert.Nil(err)
+
+	assert.Equal(*nuid, submissionResponseBody.NUID)
+
+	assert.False(submissionResponseBody.Correct)
+
+	assert.Equal(submitted, submissionResponseBody.Submission)
+
+	assert.Equal(len(submissionResponseBody.Solution), len(submissionResponseBody.Diff))
+
+	assert.Equal("foo", *submissionResponseBody.Diff[0].Given)
+
+	assert.Equal(submissionResponseBody.Solution[0], *submissionResponseBody.Diff[0].Expected)
+
+	assert.Equal(domain.CaseIncorrect, submissionResponseBody.Diff[0].Result)
+
+	assert.Nil(submissionResponseBody.Diff[2].Given)
+
+	assert.Equal(domain.CaseMissing, submissionResponseBody.Diff[2].Result)
+}
+
+func TestSubmission_ReturnsA400ForInvalidID(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	badID := "foo"
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/submission/%s", app.Address, badID), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(400, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeSubmissionIDInvalid, problem.Code)
+
+	assert.Equal(fmt.Sprintf("invalid submission ID %s", badID), problem.Detail)
+}
+
+func TestSubmission_ReturnsA404ForIDThatDoesNotExistInDB(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/submission/%d", app.Address, 1), nil))
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(404, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeSubmissionNotFound, problem.Code)
+
+	assert.Equal("Submission with ID 1 not found!", problem.Detail)
+}
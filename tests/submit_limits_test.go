@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+var limitedAppSpawners = map[string]func(func(*config.Settings)) (TestApp, error){
+	"memory": SpawnMemoryAppWith,
+	"sqlite": SpawnSQLiteAppWith,
+}
+
+func TestSubmit_ReturnsA429OnceAttemptsAreExhausted(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(settings *config.Settings) {
+				settings.Challenge.MaxAttempts = 2
+			})
+
+			assert.Nil(err)
+
+			registerResp, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			for _, remaining := range []int{1, 0} {
+				resp, err := SubmitSolution(app, registerResp, []string{"wrong"})
+
+				assert.Nil(err)
+				assert.Equal(200, resp.StatusCode)
+
+				var responseBody handlers.SubmitResponseBody
+
+				assert.Nil(json.NewDecoder(resp.Body).Decode(&responseBody))
+				assert.NotNil(responseBody.RemainingAttempts)
+				assert.Equal(remaining, *responseBody.RemainingAttempts)
+			}
+
+			resp, err := SubmitSolution(app, registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assert.Equal(429, resp.StatusCode)
+
+			problem, err := GetProblemFromResponse(resp)
+
+			assert.Nil(err)
+			assert.Equal(handlers.CodeAttemptsExhausted, problem.Code)
+		})
+	}
+}
+
+func TestSubmit_ReturnsA429WithRetryAfterDuringCooldown(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(settings *config.Settings) {
+				settings.Challenge.MinAttemptInterval = time.Hour
+			})
+
+			assert.Nil(err)
+
+			registerResp, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			resp, err := SubmitSolution(app, registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assert.Equal(200, resp.StatusCode)
+
+			var responseBody handlers.SubmitResponseBody
+
+			assert.Nil(json.NewDecoder(resp.Body).Decode(&responseBody))
+			assert.Nil(responseBody.RemainingAttempts)
+
+			resp, err = SubmitSolution(app, registerResp, []string{"wrong"})
+
+			assert.Nil(err)
+			assert.Equal(429, resp.StatusCode)
+
+			retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+
+			assert.Nil(err)
+			assert.True(retryAfter > 3500 && retryAfter <= 3600)
+
+			problem, err := GetProblemFromResponse(resp)
+
+			assert.Nil(err)
+			assert.Equal(handlers.CodeSubmissionCooldown, problem.Code)
+		})
+	}
+}
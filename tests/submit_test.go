@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http/httptest"
 	"testing"
 
@@ -86,13 +85,13 @@ func TestSubmit_ReturnsA400ForInvalidToken(t *testing.T) {
 
 	assert.Equal(400, submitResp.StatusCode)
 
-	body, err = io.ReadAll(submitResp.Body)
+	problem, err := GetProblemFromResponse(submitResp)
 
 	assert.Nil(err)
 
-	responseString := string(body)
+	assert.Equal(handlers.CodeTokenInvalid, problem.Code)
 
-	assert.Equal(fmt.Sprintf("invalid token %s", invalidToken), responseString)
+	assert.Equal(fmt.Sprintf("invalid token %s", invalidToken), problem.Detail)
 }
 
 func TestSubmit_ReturnsIncorrectAfterSubmittingCorrectSolutionThenIncorrectSolution(t *testing.T) {
@@ -129,9 +128,9 @@ func TestSubmit_ReturnsIncorrectAfterSubmittingCorrectSolutionThenIncorrectSolut
 
 	assert.Nil(err)
 
-	assert.Equal(200, forgotTokenResp.StatusCode)
+	assert.Equal(202, forgotTokenResp.StatusCode)
 
-	token, err := GetTokenFromResponse(forgotTokenResp)
+	token, err := GetTokenFromOutbox(app, SampleEmail)
 
 	if err != nil {
 		t.Errorf("Failed to get token from response: %v", err)
@@ -153,3 +152,45 @@ func TestSubmit_ReturnsIncorrectAfterSubmittingCorrectSolutionThenIncorrectSolut
 
 	assert.Equal("Incorrect Solution", submitResponseBody.Message)
 }
+
+func TestSubmit_ReturnsNumCorrectWhenScoreIsRevealed(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	submitResp, err := SubmitSolution(app, registerResp, []string{})
+
+	assert.Nil(err)
+
+	assert.Equal(200, submitResp.StatusCode)
+
+	var submitResponseBody handlers.SubmitResponseBody
+
+	err = json.NewDecoder(submitResp.Body).Decode(&submitResponseBody)
+
+	assert.Nil(err)
+
+	assert.False(submitResponseBody.Correct)
+
+	assert.NotNil(submitResponseBody.NumCorrect)
+
+	assert.Equal(0, *submitResponseBody.NumCorrect)
+
+	var dbResult struct {
+		Score      int     `db:"score"`
+		Percentage float64 `db:"percentage"`
+	}
+
+	err = app.Conn.Get(&dbResult, "SELECT score, percentage FROM submissions;")
+
+	assert.Nil(err)
+
+	assert.Equal(0, dbResult.Score)
+
+	assert.Equal(0.0, dbResult.Percentage)
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func asDuration(completion handlers.TimeToCompletion) time.Duration {
+	return time.Duration(completion.Seconds)*time.Second + time.Duration(completion.Nanos)
+}
+
+func getApplicant(app TestApp, nuid string) (*handlers.ApplicantResponse, error) {
+	req := AuthorizeAdmin(app, httptest.NewRequest("GET", fmt.Sprintf("%s/applicant/%s", app.Address, nuid), nil))
+
+	resp, err := app.App.Test(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var applicant handlers.ApplicantResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&applicant); err != nil {
+		return nil, err
+	}
+
+	return &applicant, nil
+}
+
+func TestTimeToCompletion_ReportsFirstCorrectAndLatestSubmission(t *testing.T) {
+	for name, spawn := range limitedAppSpawners {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			app, err := spawn(func(*config.Settings) {})
+
+			assert.Nil(err)
+
+			registerResp, err := RegisterSampleApplicant(app)
+
+			assert.Nil(err)
+
+			solution, err := CorrectSolution(registerResp)
+
+			assert.Nil(err)
+
+			for _, submission := range [][]string{{"wrong"}, {"wrong"}} {
+				resp, err := SubmitSolution(app, registerResp, submission)
+
+				assert.Nil(err)
+				assert.Equal(200, resp.StatusCode)
+			}
+
+			applicant, err := getApplicant(app, "002172052")
+
+			assert.Nil(err)
+			assert.Nil(applicant.TimeToFirstCorrect)
+			assert.Nil(applicant.AttemptsBeforeSuccess)
+
+			for _, submission := range [][]string{solution, {"wrong"}} {
+				resp, err := SubmitSolution(app, registerResp, submission)
+
+				assert.Nil(err)
+				assert.Equal(200, resp.StatusCode)
+			}
+
+			applicant, err = getApplicant(app, "002172052")
+
+			assert.Nil(err)
+			assert.False(applicant.Correct)
+			assert.NotNil(applicant.AttemptsBeforeSuccess)
+			assert.Equal(2, *applicant.AttemptsBeforeSuccess)
+			assert.NotNil(applicant.TimeToFirstCorrect)
+			assert.True(asDuration(*applicant.TimeToFirstCorrect) <= asDuration(applicant.TimeToLatestSubmission))
+			assert.Equal(applicant.TimeToLatestSubmission, applicant.TimeToCompletion)
+
+			for _, completion := range []handlers.TimeToCompletion{*applicant.TimeToFirstCorrect, applicant.TimeToLatestSubmission} {
+				assert.True(completion.Nanos >= 0 && completion.Nanos < int(time.Second))
+				assert.Regexp(`^PT`, completion.ISO8601)
+			}
+		})
+	}
+}
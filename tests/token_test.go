@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/db"
+	"github.com/garrettladley/generate_coding_challenge_server_go/handlers"
+	"github.com/garrettladley/generate_coding_challenge_server_go/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func adminTokenRequest(app TestApp, method string, nuid string, action string) (*http.Response, error) {
+	req := AuthorizeAdmin(app, httptest.NewRequest(method, fmt.Sprintf("%s/applicant/%s/token/%s", app.Address, nuid, action), nil))
+
+	return app.App.Test(req)
+}
+
+func assertTokenRevoked(assert *assert.Assertions, resp *http.Response) {
+	assert.Equal(403, resp.StatusCode)
+
+	problem, err := GetProblemFromResponse(resp)
+
+	assert.Nil(err)
+
+	assert.Equal(handlers.CodeTokenRevoked, problem.Code)
+}
+
+func testTokenRevocationAndRotation(t *testing.T, app TestApp) {
+	assert := assert.New(t)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	resp, err := adminTokenRequest(app, "POST", "002172052", "revoke")
+
+	assert.Nil(err)
+	assert.Equal(204, resp.StatusCode)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+	assertTokenRevoked(assert, resp)
+
+	resp, err = SubmitSolution(app, registerResp, registerResp.Challenge)
+
+	assert.Nil(err)
+	assertTokenRevoked(assert, resp)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/forgot_token/002172052", app.Address), nil))
+
+	assert.Nil(err)
+	assert.Equal(202, resp.StatusCode)
+
+	resp, err = adminTokenRequest(app, "POST", "002172052", "rotate")
+
+	assert.Nil(err)
+	assert.Equal(200, resp.StatusCode)
+
+	var rotated handlers.RotateTokenResponse
+
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&rotated))
+	assert.NotEqual(registerResp.Token, rotated.Token)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, rotated.Token), nil))
+
+	assert.Nil(err)
+	assert.Equal(200, resp.StatusCode)
+
+	resp, err = app.App.Test(httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil))
+
+	assert.Nil(err)
+	assert.Equal(404, resp.StatusCode)
+
+	resp, err = adminTokenRequest(app, "GET", "002172052", "history")
+
+	assert.Nil(err)
+	assert.Equal(200, resp.StatusCode)
+
+	var history []handlers.TokenHistoryEntry
+
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&history))
+	assert.Equal(3, len(history))
+
+	assert.Equal(storage.TokenIssued, history[0].Action)
+	assert.Equal(storage.ApplicantActor, history[0].Actor)
+	assert.Equal(storage.TokenRevoked, history[1].Action)
+	assert.True(strings.HasPrefix(history[1].Actor, "api_key:"))
+	assert.Equal(storage.TokenRotated, history[2].Action)
+	assert.Equal(history[1].Actor, history[2].Actor)
+}
+
+func TestTokenRevocation_MemoryStorage(t *testing.T) {
+	app, err := SpawnMemoryApp()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testTokenRevocationAndRotation(t, app)
+}
+
+func TestTokenRevocation_SQLiteStorage(t *testing.T) {
+	app, err := SpawnSQLiteApp()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testTokenRevocationAndRotation(t, app)
+}
+
+func TestTokenRevocation_UnknownApplicant(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnMemoryApp()
+
+	assert.Nil(err)
+
+	for _, request := range []struct {
+		method string
+		action string
+	}{
+		{"POST", "rotate"},
+		{"POST", "revoke"},
+		{"GET", "history"},
+	} {
+		resp, err := adminTokenRequest(app, request.method, "002172052", request.action)
+
+		assert.Nil(err)
+		assert.Equal(404, resp.StatusCode)
+	}
+
+	resp, err := adminTokenRequest(app, "POST", "not-a-nuid", "revoke")
+
+	assert.Nil(err)
+	assert.Equal(400, resp.StatusCode)
+}
+
+func TestTokenRevocation_HistoryIsEmptyForApplicantsRegisteredBeforeHistory(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnSQLiteApp()
+
+	assert.Nil(err)
+
+	_, err = RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	_, err = app.Conn.Exec("DELETE FROM token_history;")
+
+	assert.Nil(err)
+
+	resp, err := adminTokenRequest(app, "GET", "002172052", "history")
+
+	assert.Nil(err)
+	assert.Equal(200, resp.StatusCode)
+
+	var history []handlers.TokenHistoryEntry
+
+	assert.Nil(json.NewDecoder(resp.Body).Decode(&history))
+	assert.Empty(history)
+}
+
+func TestCLI_RevokesTokensAndShowsHistory(t *testing.T) {
+	assert := assert.New(t)
+	settings := sqliteCLISettings(t)
+
+	assert.Nil(db.Migrate(settings.Database))
+
+	out, err := executeCLI(settings, "seed", "-count", "1")
+
+	assert.Nil(err)
+
+	nuid := strings.Split(strings.TrimSpace(out), "\t")[0]
+
+	out, err = executeCLI(settings, "token", "revoke", nuid)
+
+	assert.Nil(err)
+	assert.Equal(fmt.Sprintf("revoked token for %s\n", nuid), out)
+
+	_, err = executeCLI(settings, "token", "rotate", nuid)
+
+	assert.Nil(err)
+
+	out, err = executeCLI(settings, "token", "history", nuid)
+
+	assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+
+	assert.Equal(3, len(lines))
+	assert.True(strings.HasSuffix(lines[0], "\tissued\tapplicant"))
+	assert.True(strings.HasSuffix(lines[1], "\trevoked\tcli"))
+	assert.True(strings.HasSuffix(lines[2], "\trotated\tcli"))
+
+	_, err = executeCLI(settings, "token", "history", "000000000")
+
+	assert.NotNil(err)
+}
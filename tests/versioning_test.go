@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garrettladley/generate_coding_challenge_server_go/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersioning_V1RoutesAreNotDeprecated(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("%s/v1/challenge/%s", app.Address, registerResp.Token), nil)
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	assert.Empty(resp.Header.Get("Deprecation"))
+	assert.Empty(resp.Header.Get("Sunset"))
+}
+
+func TestVersioning_LegacyRoutesAreDeprecatedAliases(t *testing.T) {
+	assert := assert.New(t)
+	app, err := SpawnApp()
+
+	assert.Nil(err)
+
+	registerResp, err := RegisterSampleApplicant(app)
+
+	assert.Nil(err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("%s/challenge/%s", app.Address, registerResp.Token), nil)
+
+	resp, err := app.App.Test(req)
+
+	assert.Nil(err)
+
+	assert.Equal(200, resp.StatusCode)
+
+	assert.Equal("true", resp.Header.Get("Deprecation"))
+	assert.Equal("Fri, 01 Jan 2027 00:00:00 GMT", resp.Header.Get("Sunset"))
+	assert.Equal(fmt.Sprintf("</v1/challenge/%s>; rel=\"successor-version\"", registerResp.Token), resp.Header.Get("Link"))
+}
+
+func TestVersioning_RejectsMalformedLegacySunset(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := config.ApplicationSettings{LegacyRoutesSunset: "2027-01-01T00:00:00Z"}
+
+	assert.Nil(settings.Validate())
+
+	settings.LegacyRoutesSunset = "January 2027"
+
+	assert.NotNil(settings.Validate())
+
+	settings.LegacyRoutesSunset = ""
+
+	assert.Nil(settings.Validate())
+}